@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+	"github.com/mjlshen/mirrosa/pkg/redact"
+	"github.com/mjlshen/mirrosa/pkg/report"
+)
+
+// inventory implements `mirrosa inventory`, which writes every AWS resource that mirrosa discovers for a cluster to
+// stdout instead of a report. It returns mirrosa's exit code.
+func inventory(args []string) int {
+	f := flag.NewFlagSet("mirrosa inventory", flag.ExitOnError)
+	source := addSourceFlags(f, "list the resources of")
+	verbose := f.Bool("v", false, "enable verbose logging")
+	format := f.String("format", "csv", "output format of the inventory, one of: "+strings.Join(report.InventoryFormats(), ", "))
+	parallelism := f.Int("parallelism", 4, "maximum number of components to validate concurrently")
+	timeout := f.Duration("timeout", 0, "maximum duration of the whole run, e.g. 10m, zero means no timeout")
+	componentTimeout := f.Duration("component-timeout", 2*time.Minute, "maximum duration of validating a single component, zero means no timeout")
+	redactOutput := f.Bool("redact", false, "pseudonymize account ids, ARNs, public IPs, hosted zone ids, and cluster names in the inventory")
+	redactKey := f.String("redact-key", os.Getenv("MIRROSA_REDACT_KEY"), "key for -redact so that pseudonyms are stable across runs, defaults to $MIRROSA_REDACT_KEY or a random key")
+	f.Parse(args)
+
+	logger := newLogger(*verbose)
+
+	if err := source.validate(); err != nil {
+		logger.Error(err.Error())
+		return exitUsage
+	}
+
+	if !slices.Contains(report.InventoryFormats(), *format) {
+		logger.Error(fmt.Sprintf("invalid -format value %q, must be one of: %s", *format, strings.Join(report.InventoryFormats(), ", ")))
+		return exitUsage
+	}
+
+	ctx, cancel := runContext(*timeout, logger)
+	defer cancel()
+
+	m, err := source.newRosaClient(ctx, logger, mirrosa.Options{})
+	if err != nil {
+		logger.Error(err.Error())
+		return exitSetup
+	}
+
+	var redactor *redact.Redactor
+	if *redactOutput {
+		if redactor, err = newRedactor(m, *redactKey); err != nil {
+			logger.Error(err.Error())
+			return exitSetup
+		}
+	}
+
+	// Resources are discovered while validating, so every component is validated even though only the inventory is
+	// written, including those whose dependencies are too broken to validate them meaningfully
+	selected, err := m.NewComponents(nil, nil)
+	if err != nil {
+		logger.Error(err.Error())
+		return exitUsage
+	}
+
+	m.Parallelism = *parallelism
+	m.ComponentTimeout = *componentTimeout
+	m.IgnoreBlockers = true
+	r, err := m.ValidateComponents(ctx, selected...)
+	if err != nil {
+		logger.Error(err.Error())
+		return exitIncomplete
+	}
+
+	var out bytes.Buffer
+	if err := report.Inventory(&out, *format, r); err != nil {
+		logger.Error(fmt.Sprintf("failed to render inventory: %s", err))
+		return exitIncomplete
+	}
+
+	if _, err := os.Stdout.Write(redactor.Bytes(out.Bytes())); err != nil {
+		logger.Error(fmt.Sprintf("failed to write inventory: %s", err))
+		return exitIncomplete
+	}
+
+	// Components that could not be evaluated may not have discovered all of their resources
+	if r.Incomplete() {
+		logger.Warn("the inventory is incomplete, some components could not be evaluated")
+		return exitIncomplete
+	}
+
+	return exitHealthy
+}
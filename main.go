@@ -1,31 +1,249 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"runtime/debug"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mjlshen/mirrosa/pkg/cassette"
+	"github.com/mjlshen/mirrosa/pkg/evidence"
 	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+	"github.com/mjlshen/mirrosa/pkg/redact"
+	"github.com/mjlshen/mirrosa/pkg/report"
 	"github.com/mjlshen/mirrosa/pkg/tui"
 )
 
+// Exit codes of mirrosa, which are documented in the README so that wrapper scripts can rely on them
+const (
+	// exitHealthy means that every component was validated without findings at or above -fail-on
+	exitHealthy = 0
+
+	// exitFindings means that validation found misconfigurations at or above -fail-on
+	exitFindings = 1
+
+	// exitUsage means that mirrosa was invoked incorrectly, the flag package also exits with 2 for unknown flags
+	exitUsage = 2
+
+	// exitIncomplete means that some components could not be evaluated, e.g. because of AWS API errors, timeouts,
+	// or an interrupt, and no findings at or above -fail-on were found in the others
+	exitIncomplete = 3
+
+	// exitSetup means that mirrosa could not start validating, e.g. because of OCM or AWS authentication failures or
+	// an unsupported cluster
+	exitSetup = 4
+)
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "inventory" {
+		os.Exit(inventory(os.Args[2:]))
+	}
+
 	f := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
-	clusterId := f.String("cluster-id", "", "OCM internal or external cluster id")
+	source := addSourceFlags(f, "validate")
 	interactive := f.Bool("i", false, "run in an interactive exploratory mode")
 	verbose := f.Bool("v", false, "enable verbose logging")
+	parallelism := f.Int("parallelism", 4, "maximum number of components to validate concurrently")
+	failOn := f.String("fail-on", string(mirrosa.SeverityError), "minimum severity of findings that fails validation: warning or error")
+	components := strings.Join(mirrosa.ComponentNames(), ", ")
+	only := f.String("only", "", "comma-separated list of components to validate, one of: "+components)
+	skip := f.String("skip", "", "comma-separated list of components to not validate, one of: "+components)
+	timeout := f.Duration("timeout", 0, "maximum duration of the whole run, e.g. 10m, zero means no timeout")
+	componentTimeout := f.Duration("component-timeout", 2*time.Minute, "maximum duration of validating a single component, zero means no timeout")
+	output := f.String("output", "text", "output format of the report, one of: "+strings.Join(report.Formats(), ", "))
+	graph := f.String("graph", "", "write the graph of discovered AWS resources to stdout instead of the report, one of: "+strings.Join(report.GraphFormats(), ", "))
+	metricsFile := f.String("metrics-file", "", "write the results as Prometheus metrics to this file, e.g. for node_exporter's textfile collector")
+	metricsAddr := f.String("metrics-addr", "", "after validating, serve the results as Prometheus metrics on this address, e.g. :9090, until interrupted")
+	evidenceDir := f.String("evidence-dir", "", "save every AWS API response, the OCM cluster, and the report to this directory")
+	evidenceTarball := f.String("evidence-tarball", "", "save every AWS API response, the OCM cluster, and the report to this .tar.gz file")
+	record := f.String("record", "", "record every AWS API call and the OCM cluster to this cassette file to study the cluster offline")
+	redactOutput := f.Bool("redact", false, "pseudonymize account ids, ARNs, public IPs, hosted zone ids, and cluster names in the report, metrics, and evidence")
+	redactKey := f.String("redact-key", os.Getenv("MIRROSA_REDACT_KEY"), "key for -redact so that pseudonyms are stable across runs, defaults to $MIRROSA_REDACT_KEY or a random key")
 	f.Parse(os.Args[1:])
 
+	logger := newLogger(*verbose)
+
+	if *interactive {
+		p := tea.NewProgram(tui.InitModel())
+		if _, err := p.Run(); err != nil {
+			logger.Error(err.Error())
+		}
+		os.Exit(0)
+	}
+
+	if err := source.validate(); err != nil {
+		logger.Error(err.Error())
+		os.Exit(exitUsage)
+	}
+
+	if *record != "" && source.replay != "" {
+		logger.Error("-record and -replay can't be used together")
+		os.Exit(exitUsage)
+	}
+
+	// A cassette must replay the real AWS responses, so it is never redacted and can't be shared like the report
+	if *record != "" && *redactOutput {
+		logger.Error("-record and -redact can't be used together, cassettes are never redacted")
+		os.Exit(exitUsage)
+	}
+
+	if !slices.Contains(report.Formats(), *output) {
+		logger.Error(fmt.Sprintf("invalid -output value %q, must be one of: %s", *output, strings.Join(report.Formats(), ", ")))
+		os.Exit(exitUsage)
+	}
+
+	if *graph != "" && !slices.Contains(report.GraphFormats(), *graph) {
+		logger.Error(fmt.Sprintf("invalid -graph value %q, must be one of: %s", *graph, strings.Join(report.GraphFormats(), ", ")))
+		os.Exit(exitUsage)
+	}
+
+	failOnSeverity := mirrosa.Severity(*failOn)
+	if failOnSeverity != mirrosa.SeverityWarning && failOnSeverity != mirrosa.SeverityError {
+		logger.Error(fmt.Sprintf("invalid -fail-on value %q, must be warning or error", *failOn))
+		os.Exit(exitUsage)
+	}
+
+	ctx, cancel := runContext(*timeout, logger)
+	defer cancel()
+
+	var (
+		clientOpts mirrosa.Options
+		observers  []mirrosa.AwsObserver
+	)
+	bundle := &evidence.Bundle{}
+	if *evidenceDir != "" || *evidenceTarball != "" {
+		observers = append(observers, bundle.Observe)
+	}
+
+	recorder := &cassette.Recorder{}
+	if *record != "" {
+		observers = append(observers, recorder.ObserveAws)
+		clientOpts.OcmObserver = recorder.ObserveOcm
+	}
+	clientOpts.AwsObserver = observeAll(observers...)
+
+	m, err := source.newRosaClient(ctx, logger, clientOpts)
+	if err != nil {
+		logger.Error(err.Error())
+		// A cluster that mirrosa can't even discover is worth studying offline too
+		if *record != "" {
+			saveCassette(recorder, *record, logger)
+		}
+		os.Exit(exitSetup)
+	}
+
+	logger.Debug("cluster info from OCM", "cluster info", *m.ClusterInfo)
+	logger.Info("who's the fairest of them all", "cluster", m.ClusterInfo.Name)
+
+	var redactor *redact.Redactor
+	if *redactOutput {
+		redactor, err = newRedactor(m, *redactKey)
+		if err != nil {
+			logger.Error(err.Error())
+			os.Exit(exitSetup)
+		}
+		bundle.Redactor = redactor
+	}
+
+	selected, err := m.NewComponents(splitList(*only), splitList(*skip))
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(exitUsage)
+	}
+
+	m.Parallelism = *parallelism
+	m.ComponentTimeout = *componentTimeout
+	r, err := m.ValidateComponents(ctx, selected...)
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(exitIncomplete)
+	}
+
+	var out bytes.Buffer
+	if *graph != "" {
+		err = report.Graph(&out, *graph, r)
+	} else {
+		err = report.Render(&out, *output, r)
+	}
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to render report: %s", err))
+		os.Exit(exitIncomplete)
+	}
+
+	if _, err := os.Stdout.Write(redactor.Bytes(out.Bytes())); err != nil {
+		logger.Error(fmt.Sprintf("failed to write report: %s", err))
+		os.Exit(exitIncomplete)
+	}
+
+	if *metricsFile != "" {
+		if err := writeMetricsFile(*metricsFile, r, redactor); err != nil {
+			logger.Error(fmt.Sprintf("failed to write metrics: %s", err))
+			os.Exit(exitIncomplete)
+		}
+	}
+
+	if *evidenceDir != "" {
+		if err := bundle.WriteDir(*evidenceDir, m.Cluster, r); err != nil {
+			logger.Error(fmt.Sprintf("failed to write evidence: %s", err))
+			os.Exit(exitIncomplete)
+		}
+		logger.Info("wrote evidence", slog.String("dir", *evidenceDir))
+	}
+
+	if *evidenceTarball != "" {
+		if err := bundle.WriteTarball(*evidenceTarball, m.Cluster, r); err != nil {
+			logger.Error(fmt.Sprintf("failed to write evidence: %s", err))
+			os.Exit(exitIncomplete)
+		}
+		logger.Info("wrote evidence", slog.String("tarball", *evidenceTarball))
+	}
+
+	if *record != "" {
+		if !saveCassette(recorder, *record, logger) {
+			os.Exit(exitIncomplete)
+		}
+	}
+
+	code := exitCode(r, failOnSeverity)
+	switch code {
+	case exitFindings:
+		logger.Error(fmt.Sprintf("%s is not the fairest of them all", m.ClusterInfo.Name))
+	case exitIncomplete:
+		logger.Error(fmt.Sprintf("could not determine if %s is the fairest of them all", m.ClusterInfo.Name))
+	default:
+		logger.Info(fmt.Sprintf("%s is the fairest of them all!", m.ClusterInfo.Name))
+	}
+
+	if *metricsAddr != "" {
+		if err := serveMetrics(*metricsAddr, r, redactor, logger); err != nil {
+			logger.Error(fmt.Sprintf("failed to serve metrics: %s", err))
+			os.Exit(exitIncomplete)
+		}
+	}
+
+	os.Exit(code)
+}
+
+// newLogger returns a logger that writes to stderr so that stdout only contains the report
+func newLogger(verbose bool) *slog.Logger {
 	opts := slog.HandlerOptions{}
-	if *verbose {
+	if verbose {
 		opts.AddSource = true
 		opts.Level = slog.LevelDebug
 	}
-	logger := slog.New(slog.NewTextHandler(os.Stdout, &opts))
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &opts))
 
 	if info, ok := debug.ReadBuildInfo(); ok {
 		logger.Debug(fmt.Sprintf("Go Version: %s", info.GoVersion))
@@ -39,42 +257,151 @@ func main() {
 		}
 	}
 
-	if *interactive {
-		p := tea.NewProgram(tui.InitModel())
-		if _, err := p.Run(); err != nil {
-			logger.Error(err.Error())
+	return logger
+}
+
+// runContext returns the context of a run, which is cancelled on the first interrupt so that a partial report can still
+// be printed, a second interrupt exits immediately as usual. A timeout of zero means no timeout.
+func runContext(timeout time.Duration, logger *slog.Logger) (context.Context, context.CancelFunc) {
+	interruptCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	stopWarning := context.AfterFunc(interruptCtx, func() {
+		stop()
+		logger.Warn("interrupted, cancelling validation (interrupt again to exit immediately)")
+	})
+	release := func() {
+		stopWarning()
+		stop()
+	}
+
+	if timeout <= 0 {
+		return interruptCtx, release
+	}
+
+	ctx, cancel := context.WithTimeout(interruptCtx, timeout)
+	return ctx, func() {
+		cancel()
+		release()
+	}
+}
+
+// newRedactor returns a Redactor for m's cluster, which also pseudonymizes its names, IDs, and base domain
+func newRedactor(m *mirrosa.Client, key string) (*redact.Redactor, error) {
+	return redact.New(key, m.ClusterInfo.Name, m.ClusterInfo.InfraName, m.ClusterInfo.BaseDomain, m.Cluster.ID(),
+		m.Cluster.ExternalID())
+}
+
+// observeAll returns an AwsObserver that calls every observer in turn, or nil if there are none
+func observeAll(observers ...mirrosa.AwsObserver) mirrosa.AwsObserver {
+	if len(observers) == 0 {
+		return nil
+	}
+
+	return func(e mirrosa.AwsExchange) {
+		for _, observe := range observers {
+			observe(e)
 		}
-		os.Exit(0)
 	}
+}
+
+// saveCassette saves everything recorded by recorder to a cassette file at path and reports whether it succeeded
+func saveCassette(recorder *cassette.Recorder, path string, logger *slog.Logger) bool {
+	if err := recorder.Save(path); err != nil {
+		logger.Error(fmt.Sprintf("failed to write cassette: %s", err))
+		return false
+	}
+
+	logger.Info("wrote cassette", slog.String("path", path))
+	return true
+}
+
+// exitCode determines mirrosa's exit code from a Report. Findings take precedence over components that could not be
+// evaluated, since they are known misconfigurations regardless of the rest.
+func exitCode(r mirrosa.Report, failOn mirrosa.Severity) int {
+	switch {
+	case r.HasFindings(failOn):
+		return exitFindings
+	case r.Incomplete():
+		return exitIncomplete
+	default:
+		return exitHealthy
+	}
+}
 
-	if *clusterId == "" {
-		logger.Error("cluster id must not be empty")
-		os.Exit(1)
+// writeMetricsFile writes r as Prometheus metrics, redacted by redactor if set, to path. The file is replaced atomically
+// so that a collector never reads a partially written file.
+func writeMetricsFile(path string, r mirrosa.Report, redactor *redact.Redactor) error {
+	var buf bytes.Buffer
+	if err := report.Metrics(&buf, r); err != nil {
+		return err
 	}
 
-	m, err := mirrosa.NewRosaClient(context.Background(), logger, *clusterId)
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
 	if err != nil {
-		logger.Error(err.Error())
-		os.Exit(1)
+		return err
 	}
+	defer os.Remove(tmp.Name())
 
-	logger.Debug("cluster info from OCM", "cluster info", *m.ClusterInfo)
-	logger.Info("who's the fairest of them all", "cluster", m.ClusterInfo.Name)
+	if _, err := tmp.Write(redactor.Bytes(buf.Bytes())); err != nil {
+		tmp.Close()
+		return err
+	}
 
-	if err := m.ValidateComponents(context.TODO(),
-		m.NewVpc(),
-		m.NewDhcpOptions(),
-		m.NewSecurityGroup(),
-		m.NewVpcEndpointService(),
-		m.NewPublicHostedZone(),
-		m.NewPrivateHostedZone(),
-		m.NewApiLoadBalancer(),
-		m.NewInstances(),
-	); err != nil {
-		logger.Error(err.Error())
-		os.Exit(1)
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// serveMetrics serves r as Prometheus metrics, redacted by redactor if set, on addr at /metrics until mirrosa is
+// interrupted
+func serveMetrics(addr string, r mirrosa.Report, redactor *redact.Redactor, logger *slog.Logger) error {
+	var buf bytes.Buffer
+	if err := report.Metrics(&buf, r); err != nil {
+		return err
+	}
+	metrics := redactor.Bytes(buf.Bytes())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", report.MetricsContentType)
+		if _, err := w.Write(metrics); err != nil {
+			logger.Error(fmt.Sprintf("failed to write metrics: %s", err))
+		}
+	})
+
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info("serving metrics until interrupted", slog.String("addr", addr))
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// splitList splits a comma-separated flag value, ignoring surrounding whitespace and empty elements
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
 	}
 
-	logger.Info(fmt.Sprintf("%s is the fairest of them all!", m.ClusterInfo.Name))
-	os.Exit(0)
+	return list
 }
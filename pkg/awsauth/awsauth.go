@@ -0,0 +1,144 @@
+// Package awsauth builds the AWS configuration mirrosa uses to call AWS from a selectable source of credentials, so
+// that mirrosa can run without backplane access, e.g. by a customer's platform team or in a sandbox account
+package awsauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// Source is where the base AWS credentials come from
+type Source string
+
+const (
+	// SourceBackplane gets credentials for the cluster's account from backplane-api, which requires OCM
+	SourceBackplane Source = "backplane"
+
+	// SourceDefault uses the default AWS SDK credential chain, e.g. environment variables, the default profile, or an
+	// instance role
+	SourceDefault Source = "default"
+
+	// SourceProfile uses a named profile from the shared AWS config and credentials files
+	SourceProfile Source = "profile"
+
+	// SourceEnv only uses static credentials from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and optional
+	// AWS_SESSION_TOKEN environment variables
+	SourceEnv Source = "env"
+)
+
+// Sources are all supported Sources
+var Sources = []Source{SourceBackplane, SourceDefault, SourceProfile, SourceEnv}
+
+// roleSessionName identifies mirrosa in the CloudTrail logs of every assumed role
+const roleSessionName = "mirrosa"
+
+// Options selects and configures the AWS credentials mirrosa uses
+type Options struct {
+	// Source is where the base credentials come from
+	Source Source
+
+	// Profile is the name of the profile for SourceProfile
+	Profile string
+
+	// RoleArns are assumed in order starting from the base credentials, each with the credentials of the previous one
+	RoleArns []string
+
+	// ExternalId is passed when assuming the last role in RoleArns, as is commonly required for cross-account roles
+	ExternalId string
+}
+
+// Validate checks that o is a supported combination of Options
+func (o Options) Validate() error {
+	if !slices.Contains(Sources, o.Source) {
+		names := make([]string, len(Sources))
+		for i, source := range Sources {
+			names[i] = string(source)
+		}
+		return fmt.Errorf("unsupported AWS credential source %q, must be one of: %s", o.Source, strings.Join(names, ", "))
+	}
+
+	if o.Source == SourceProfile && o.Profile == "" {
+		return errors.New("a profile is required for the profile AWS credential source")
+	}
+
+	if o.Source != SourceProfile && o.Profile != "" {
+		return fmt.Errorf("a profile can only be used with the profile AWS credential source, not %s", o.Source)
+	}
+
+	if o.Source == SourceBackplane && len(o.RoleArns) > 0 {
+		return errors.New("roles can't be assumed with backplane AWS credentials, which already assume a role in the cluster's account")
+	}
+
+	if o.ExternalId != "" && len(o.RoleArns) == 0 {
+		return errors.New("an external id can only be used when assuming a role")
+	}
+
+	return nil
+}
+
+// LoadConfig returns an AWS configuration for region with credentials from any Source except SourceBackplane, which
+// needs OCM and is handled by the ocm package, and assumes o.RoleArns on top of them
+func LoadConfig(ctx context.Context, region string, o Options) (aws.Config, error) {
+	if err := o.Validate(); err != nil {
+		return aws.Config{}, err
+	}
+
+	opts := []func(*config.LoadOptions) error{config.WithRegion(region)}
+	switch o.Source {
+	case SourceBackplane:
+		return aws.Config{}, errors.New("backplane AWS credentials must be loaded with OCM")
+	case SourceProfile:
+		opts = append(opts, config.WithSharedConfigProfile(o.Profile))
+	case SourceEnv:
+		provider, err := envCredentials()
+		if err != nil {
+			return aws.Config{}, err
+		}
+		opts = append(opts, config.WithCredentialsProvider(provider))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load %s AWS credentials: %w", o.Source, err)
+	}
+
+	return AssumeRoles(cfg, o.RoleArns, o.ExternalId), nil
+}
+
+// envCredentials returns static credentials from the standard AWS environment variables
+func envCredentials() (aws.CredentialsProvider, error) {
+	accessKeyId := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretAccessKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKeyId == "" || secretAccessKey == "" {
+		return nil, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set for the env AWS credential source")
+	}
+
+	return credentials.NewStaticCredentialsProvider(accessKeyId, secretAccessKey, os.Getenv("AWS_SESSION_TOKEN")), nil
+}
+
+// AssumeRoles returns a copy of cfg whose credentials come from assuming each role in roleArns in turn, passing
+// externalId when assuming the last one. Roles are only assumed once credentials are first needed.
+func AssumeRoles(cfg aws.Config, roleArns []string, externalId string) aws.Config {
+	cfg = cfg.Copy()
+	for i, roleArn := range roleArns {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), roleArn, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = roleSessionName
+			if i == len(roleArns)-1 && externalId != "" {
+				o.ExternalID = aws.String(externalId)
+			}
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+
+	return cfg
+}
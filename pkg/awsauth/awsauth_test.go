@@ -0,0 +1,162 @@
+package awsauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+func TestOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      Options
+		expectErr bool
+	}{
+		{name: "backplane", opts: Options{Source: SourceBackplane}},
+		{name: "default with role chain", opts: Options{Source: SourceDefault, RoleArns: []string{"arn:aws:iam::123456789012:role/a", "arn:aws:iam::210987654321:role/b"}, ExternalId: "mock"}},
+		{name: "profile", opts: Options{Source: SourceProfile, Profile: "mock"}},
+		{name: "unsupported source", opts: Options{Source: "vault"}, expectErr: true},
+		{name: "profile without name", opts: Options{Source: SourceProfile}, expectErr: true},
+		{name: "name without profile", opts: Options{Source: SourceEnv, Profile: "mock"}, expectErr: true},
+		{name: "backplane with role", opts: Options{Source: SourceBackplane, RoleArns: []string{"arn:aws:iam::123456789012:role/a"}}, expectErr: true},
+		{name: "external id without role", opts: Options{Source: SourceDefault, ExternalId: "mock"}, expectErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.opts.Validate(); (err != nil) != test.expectErr {
+				t.Errorf("expected err: %v, got %v", test.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	credentialsFile := filepath.Join(dir, "credentials")
+	if err := os.WriteFile(credentialsFile, []byte("[mock]\naws_access_key_id = PROFILEKEY\naws_secret_access_key = secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+
+	tests := []struct {
+		name        string
+		opts        Options
+		env         map[string]string
+		expectedKey string
+		expectErr   bool
+	}{
+		{
+			name:        "env",
+			opts:        Options{Source: SourceEnv},
+			env:         map[string]string{"AWS_ACCESS_KEY_ID": "ENVKEY", "AWS_SECRET_ACCESS_KEY": "secret"},
+			expectedKey: "ENVKEY",
+		},
+		{
+			name:      "env without variables",
+			opts:      Options{Source: SourceEnv},
+			env:       map[string]string{"AWS_ACCESS_KEY_ID": "", "AWS_SECRET_ACCESS_KEY": ""},
+			expectErr: true,
+		},
+		{
+			name:        "profile",
+			opts:        Options{Source: SourceProfile, Profile: "mock"},
+			expectedKey: "PROFILEKEY",
+		},
+		{
+			name:      "backplane",
+			opts:      Options{Source: SourceBackplane},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for k, v := range test.env {
+				t.Setenv(k, v)
+			}
+
+			cfg, err := LoadConfig(context.TODO(), "us-east-1", test.opts)
+			if err != nil {
+				if !test.expectErr {
+					t.Fatalf("expected no err, got %v", err)
+				}
+				return
+			}
+
+			if test.expectErr {
+				t.Fatal("expected err, got nil")
+			}
+
+			creds, err := cfg.Credentials.Retrieve(context.TODO())
+			if err != nil {
+				t.Fatalf("expected no err, got %v", err)
+			}
+
+			if creds.AccessKeyID != test.expectedKey || cfg.Region != "us-east-1" {
+				t.Errorf("expected %s in us-east-1, got %s in %s", test.expectedKey, creds.AccessKeyID, cfg.Region)
+			}
+		})
+	}
+}
+
+func TestAssumeRoles(t *testing.T) {
+	type assumeRole struct {
+		roleArn    string
+		externalId string
+	}
+
+	var (
+		mu      sync.Mutex
+		assumed []assumeRole
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("failed to parse AssumeRole request: %v", err)
+		}
+
+		mu.Lock()
+		assumed = append(assumed, assumeRole{roleArn: r.Form.Get("RoleArn"), externalId: r.Form.Get("ExternalId")})
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/xml")
+		w.Write([]byte(`<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/"><AssumeRoleResult>` +
+			`<Credentials><AccessKeyId>ASSUMEDKEY</AccessKeyId><SecretAccessKey>secret</SecretAccessKey>` +
+			`<SessionToken>token</SessionToken><Expiration>2099-01-01T00:00:00Z</Expiration></Credentials>` +
+			`</AssumeRoleResult></AssumeRoleResponse>`))
+	}))
+	defer server.Close()
+
+	cfg := aws.Config{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("BASEKEY", "secret", ""),
+	}
+
+	roleArns := []string{"arn:aws:iam::123456789012:role/jump", "arn:aws:iam::210987654321:role/mirrosa"}
+	creds, err := AssumeRoles(cfg, roleArns, "mock-external-id").Credentials.Retrieve(context.TODO())
+	if err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	if creds.AccessKeyID != "ASSUMEDKEY" {
+		t.Errorf("expected the assumed role's credentials, got %s", creds.AccessKeyID)
+	}
+
+	expected := []assumeRole{
+		{roleArn: roleArns[0]},
+		{roleArn: roleArns[1], externalId: "mock-external-id"},
+	}
+	if !reflect.DeepEqual(assumed, expected) {
+		t.Errorf("expected roles to be assumed in order as %+v, got %+v", expected, assumed)
+	}
+}
@@ -0,0 +1,170 @@
+// Package cassette records the AWS API calls and OCM cluster lookup of a mirrosa run into a cassette file, so that a
+// cluster can be captured once and studied offline later
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// Version is the version of the cassette format, which is incremented whenever a field is changed or removed
+const Version = 1
+
+// Cassette is everything mirrosa received from OCM and AWS during a run
+type Cassette struct {
+	// Version is the version of the cassette format
+	Version int `json:"version"`
+
+	// Recorded is when the cassette was saved
+	Recorded time.Time `json:"recorded"`
+
+	// ClusterId is the cluster id mirrosa was given, which may be a name, internal id, or external id
+	ClusterId string `json:"clusterId"`
+
+	// Cluster is the cluster object that OCM returned for ClusterId
+	Cluster json.RawMessage `json:"cluster,omitempty"`
+
+	// Interactions are the AWS API calls in the order they were made
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single AWS API call and its outcome
+type Interaction struct {
+	// Service is the AWS service ID, e.g. EC2
+	Service string `json:"service"`
+
+	// Operation is the name of the AWS API operation, e.g. DescribeVpcs
+	Operation string `json:"operation"`
+
+	// Input is the JSON form of the call's input, e.g. *ec2.DescribeVpcsInput
+	Input json.RawMessage `json:"input"`
+
+	// Output is the JSON form of the call's output, e.g. *ec2.DescribeVpcsOutput, which is empty if the call failed
+	Output json.RawMessage `json:"output,omitempty"`
+
+	// Error is the error returned by the call, if any
+	Error *Error `json:"error,omitempty"`
+}
+
+// Error is an error returned by an AWS API call
+type Error struct {
+	// Code is the AWS API error code, e.g. UnauthorizedOperation, if the error came from AWS
+	Code string `json:"code,omitempty"`
+
+	// Message is the error message
+	Message string `json:"message"`
+}
+
+// Recorder collects a Cassette. Its ObserveAws and ObserveOcm methods are a mirrosa.AwsObserver and
+// mirrosa.OcmObserver and are safe for concurrent use.
+type Recorder struct {
+	mu       sync.Mutex
+	cassette Cassette
+	err      error
+}
+
+// ObserveAws records an AWS API call
+func (r *Recorder) ObserveAws(e mirrosa.AwsExchange) {
+	i := Interaction{
+		Service:   e.Service,
+		Operation: e.Operation,
+	}
+
+	input, err := json.Marshal(e.Input)
+	if err != nil {
+		r.fail(fmt.Errorf("failed to marshal %s:%s input: %w", e.Service, e.Operation, err))
+		return
+	}
+	i.Input = input
+
+	if e.Err != nil {
+		i.Error = &Error{Message: e.Err.Error()}
+		var apiErr smithy.APIError
+		if errors.As(e.Err, &apiErr) {
+			i.Error = &Error{Code: apiErr.ErrorCode(), Message: apiErr.ErrorMessage()}
+		}
+	} else {
+		output, err := json.Marshal(e.Output)
+		if err != nil {
+			r.fail(fmt.Errorf("failed to marshal %s:%s output: %w", e.Service, e.Operation, err))
+			return
+		}
+		i.Output = output
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+}
+
+// ObserveOcm records the cluster object that OCM returned for clusterId
+func (r *Recorder) ObserveOcm(clusterId string, cluster *cmv1.Cluster) {
+	var buf bytes.Buffer
+	if err := cmv1.MarshalCluster(cluster, &buf); err != nil {
+		r.fail(fmt.Errorf("failed to marshal cluster: %w", err))
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.ClusterId = clusterId
+	r.cassette.Cluster = buf.Bytes()
+}
+
+// fail remembers the first error while recording, which is returned by Save
+func (r *Recorder) fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// Save writes everything recorded so far to a cassette file at path. The file is replaced atomically so that an
+// interrupted run never leaves a truncated cassette behind.
+func (r *Recorder) Save(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+
+	c := r.cassette
+	c.Version = Version
+	c.Recorded = time.Now().UTC()
+	if c.Interactions == nil {
+		c.Interactions = []Interaction{}
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
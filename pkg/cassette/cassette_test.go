@@ -0,0 +1,74 @@
+package cassette
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+func TestRecorder_Save(t *testing.T) {
+	cluster, err := cmv1.NewCluster().ID("mock-id").Name("mock").Build()
+	if err != nil {
+		t.Fatalf("failed to build cluster: %v", err)
+	}
+
+	r := &Recorder{}
+	r.ObserveOcm("mock", cluster)
+	r.ObserveAws(mirrosa.AwsExchange{
+		Service:   "EC2",
+		Operation: "DescribeVpcs",
+		Started:   time.Now(),
+		Input:     &ec2.DescribeVpcsInput{VpcIds: []string{"vpc-1"}},
+		Output:    &ec2.DescribeVpcsOutput{Vpcs: []types.Vpc{{VpcId: aws.String("vpc-1")}}},
+	})
+	r.ObserveAws(mirrosa.AwsExchange{
+		Service:   "EC2",
+		Operation: "DescribeDhcpOptions",
+		Started:   time.Now(),
+		Input:     &ec2.DescribeDhcpOptionsInput{},
+		Err:       &smithy.GenericAPIError{Code: "UnauthorizedOperation", Message: "not authorized"},
+	})
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := r.Save(path); err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		t.Fatalf("expected valid JSON, got %v", err)
+	}
+
+	if c.Version != Version || c.ClusterId != "mock" || len(c.Interactions) != 2 {
+		t.Fatalf("expected a version %d cassette for mock with 2 interactions, got %s", Version, data)
+	}
+
+	recorded, err := cmv1.UnmarshalCluster([]byte(c.Cluster))
+	if err != nil || recorded.ID() != "mock-id" {
+		t.Errorf("expected the OCM cluster to be recorded, got %s", c.Cluster)
+	}
+
+	var output ec2.DescribeVpcsOutput
+	if err := json.Unmarshal(c.Interactions[0].Output, &output); err != nil || aws.ToString(output.Vpcs[0].VpcId) != "vpc-1" {
+		t.Errorf("expected the DescribeVpcs output to be recorded, got %s", c.Interactions[0].Output)
+	}
+
+	expectedErr := Error{Code: "UnauthorizedOperation", Message: "not authorized"}
+	if c.Interactions[1].Error == nil || *c.Interactions[1].Error != expectedErr || c.Interactions[1].Output != nil {
+		t.Errorf("expected %+v to be recorded, got %+v", expectedErr, c.Interactions[1])
+	}
+}
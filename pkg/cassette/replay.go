@@ -0,0 +1,185 @@
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// clientTypes maps each AWS service ID to the type of its client, whose methods determine the output type of each of
+// the service's operations
+var clientTypes = map[string]reflect.Type{
+	ec2.ServiceID:     reflect.TypeOf(&ec2.Client{}),
+	elbv2.ServiceID:   reflect.TypeOf(&elbv2.Client{}),
+	route53.ServiceID: reflect.TypeOf(&route53.Client{}),
+}
+
+// Load reads a cassette file saved by Recorder.Save
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cassette{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+
+	if c.Version != Version {
+		return nil, fmt.Errorf("unsupported cassette version %d in %s, expected %d", c.Version, path, Version)
+	}
+
+	return c, nil
+}
+
+// OcmCluster returns the cluster object that OCM returned when the cassette was recorded
+func (c *Cassette) OcmCluster() (*cmv1.Cluster, error) {
+	if len(c.Cluster) == 0 {
+		return nil, errors.New("cassette does not contain an OCM cluster")
+	}
+
+	return cmv1.UnmarshalCluster([]byte(c.Cluster))
+}
+
+// AwsConfig returns an aws.Config for region whose API calls are answered from the cassette's Interactions instead
+// of AWS. Calls that were not recorded fail, and no call ever reaches the network or needs credentials.
+func (c *Cassette) AwsConfig(region string) (aws.Config, error) {
+	p, err := newPlayer(c.Interactions)
+	if err != nil {
+		return aws.Config{}, err
+	}
+
+	return aws.Config{
+		Region:      region,
+		Credentials: aws.AnonymousCredentials{},
+		HTTPClient:  offlineHttpClient{},
+		APIOptions:  []func(*middleware.Stack) error{p.addToStack},
+	}, nil
+}
+
+// offlineHttpClient fails every request, so that a call that slips past the player never reaches AWS
+type offlineHttpClient struct{}
+
+func (offlineHttpClient) Do(req *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("refusing to send %s %s while replaying a cassette", req.Method, req.URL)
+}
+
+// player answers AWS API calls with recorded Interactions. Identical calls are answered in the order they were
+// recorded, repeating the last answer once they run out.
+type player struct {
+	mu           sync.Mutex
+	interactions map[string][]Interaction
+	played       map[string]int
+}
+
+func newPlayer(interactions []Interaction) (*player, error) {
+	p := &player{
+		interactions: map[string][]Interaction{},
+		played:       map[string]int{},
+	}
+
+	for _, i := range interactions {
+		key, err := interactionKey(i.Service, i.Operation, i.Input)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input of %s:%s in cassette: %w", i.Service, i.Operation, err)
+		}
+		p.interactions[key] = append(p.interactions[key], i)
+	}
+
+	return p, nil
+}
+
+// interactionKey identifies identical calls by their service, operation, and input, regardless of how the input was
+// indented in the cassette
+func interactionKey(service, operation string, input []byte) (string, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, input); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/%s/%s", service, operation, buf.String()), nil
+}
+
+// next returns the recorded Interaction that answers a call
+func (p *player) next(service, operation string, params interface{}) (Interaction, error) {
+	input, err := json.Marshal(params)
+	if err != nil {
+		return Interaction{}, err
+	}
+
+	key, err := interactionKey(service, operation, input)
+	if err != nil {
+		return Interaction{}, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	recorded := p.interactions[key]
+	if len(recorded) == 0 {
+		return Interaction{}, fmt.Errorf("cassette has no recorded %s:%s call with input %s", service, operation, input)
+	}
+
+	i := recorded[min(p.played[key], len(recorded)-1)]
+	p.played[key]++
+
+	return i, nil
+}
+
+// output returns the recorded output of an Interaction as the output type of its operation, e.g.
+// *ec2.DescribeVpcsOutput, or its recorded error
+func (i Interaction) output() (interface{}, error) {
+	if i.Error != nil {
+		if i.Error.Code != "" {
+			return nil, &smithy.GenericAPIError{Code: i.Error.Code, Message: i.Error.Message}
+		}
+		return nil, errors.New(i.Error.Message)
+	}
+
+	clientType, ok := clientTypes[i.Service]
+	if !ok {
+		return nil, fmt.Errorf("unsupported AWS service %q in cassette", i.Service)
+	}
+
+	method, ok := clientType.MethodByName(i.Operation)
+	if !ok || method.Type.NumOut() != 2 {
+		return nil, fmt.Errorf("unsupported %s operation %q in cassette", i.Service, i.Operation)
+	}
+
+	out := reflect.New(method.Type.Out(0).Elem()).Interface()
+	if err := json.Unmarshal(i.Output, out); err != nil {
+		return nil, fmt.Errorf("invalid output of %s:%s in cassette: %w", i.Service, i.Operation, err)
+	}
+
+	return out, nil
+}
+
+// addToStack adds the player to the start of an AWS API client's middleware stack, for use in aws.Config.APIOptions.
+// Calls are answered without ever reaching the rest of the stack, e.g. signing, retries, or sending the request.
+func (p *player) addToStack(stack *middleware.Stack) error {
+	replay := middleware.InitializeMiddlewareFunc("MirrosaCassettePlayer", func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+		i, err := p.next(middleware.GetServiceID(ctx), middleware.GetOperationName(ctx), in.Parameters)
+		if err != nil {
+			return middleware.InitializeOutput{}, middleware.Metadata{}, err
+		}
+
+		out, err := i.output()
+		return middleware.InitializeOutput{Result: out}, middleware.Metadata{}, err
+	})
+
+	return stack.Initialize.Add(replay, middleware.Before)
+}
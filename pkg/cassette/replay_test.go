@@ -0,0 +1,180 @@
+package cassette
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// mustInteraction returns an Interaction for an EC2 call, as recorded by Recorder
+func mustInteraction(t *testing.T, operation string, input, output interface{}, apiErr *Error) Interaction {
+	t.Helper()
+	i := Interaction{Service: ec2.ServiceID, Operation: operation, Error: apiErr}
+
+	var err error
+	if i.Input, err = json.MarshalIndent(input, "", "  "); err != nil {
+		t.Fatal(err)
+	}
+
+	if output != nil {
+		if i.Output, err = json.Marshal(output); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return i
+}
+
+func TestCassette_AwsConfig(t *testing.T) {
+	c := &Cassette{
+		Version: Version,
+		Interactions: []Interaction{
+			mustInteraction(t, "DescribeVpcs", &ec2.DescribeVpcsInput{VpcIds: []string{"vpc-1"}},
+				&ec2.DescribeVpcsOutput{Vpcs: []types.Vpc{{VpcId: aws.String("vpc-1")}}}, nil),
+			mustInteraction(t, "DescribeDhcpOptions", &ec2.DescribeDhcpOptionsInput{}, nil,
+				&Error{Code: "UnauthorizedOperation", Message: "not authorized"}),
+		},
+	}
+
+	cfg, err := c.AwsConfig("us-east-1")
+	if err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+	client := ec2.NewFromConfig(cfg)
+
+	// Identical calls repeat the last recorded answer
+	for range 2 {
+		vpcs, err := client.DescribeVpcs(context.TODO(), &ec2.DescribeVpcsInput{VpcIds: []string{"vpc-1"}})
+		if err != nil {
+			t.Fatalf("expected no err, got %v", err)
+		}
+
+		if len(vpcs.Vpcs) != 1 || aws.ToString(vpcs.Vpcs[0].VpcId) != "vpc-1" {
+			t.Errorf("expected the recorded DescribeVpcs output, got %+v", vpcs.Vpcs)
+		}
+	}
+
+	var apiErr smithy.APIError
+	_, err = client.DescribeDhcpOptions(context.TODO(), &ec2.DescribeDhcpOptionsInput{})
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "UnauthorizedOperation" {
+		t.Errorf("expected the recorded UnauthorizedOperation error, got %v", err)
+	}
+
+	if _, err := client.DescribeVpcs(context.TODO(), &ec2.DescribeVpcsInput{VpcIds: []string{"vpc-2"}}); err == nil {
+		t.Error("expected an err for a call that was not recorded, got nil")
+	}
+}
+
+func TestReplay_NewRosaClientFromCluster(t *testing.T) {
+	cluster, err := cmv1.NewCluster().
+		ID("mock-id").
+		Name("mock").
+		InfraID("mock-abcde").
+		CloudProvider(cmv1.NewCloudProvider().ID("aws")).
+		Product(cmv1.NewProduct().ID("rosa")).
+		CCS(cmv1.NewCCS().Enabled(true)).
+		Region(cmv1.NewCloudRegion().ID("us-east-1")).
+		Build()
+	if err != nil {
+		t.Fatalf("failed to build cluster: %v", err)
+	}
+
+	// Record the calls mirrosa makes to discover a non-BYOVPC cluster, as if -record had been used
+	r := &Recorder{}
+	r.ObserveOcm("mock", cluster)
+	r.ObserveAws(mirrosa.AwsExchange{
+		Service:   ec2.ServiceID,
+		Operation: "DescribeVpcs",
+		Input: &ec2.DescribeVpcsInput{Filters: []types.Filter{
+			{Name: aws.String("tag:Name"), Values: []string{"mock-abcde-vpc"}},
+			{Name: aws.String("tag:kubernetes.io/cluster/mock-abcde"), Values: []string{"owned"}},
+		}},
+		Output: &ec2.DescribeVpcsOutput{Vpcs: []types.Vpc{{VpcId: aws.String("vpc-1")}}},
+	})
+	r.ObserveAws(mirrosa.AwsExchange{
+		Service:   ec2.ServiceID,
+		Operation: "DescribeSubnets",
+		Input: &ec2.DescribeSubnetsInput{Filters: []types.Filter{
+			{Name: aws.String("vpc-id"), Values: []string{"vpc-1"}},
+		}},
+		Output: &ec2.DescribeSubnetsOutput{Subnets: []types.Subnet{{SubnetId: aws.String("subnet-1")}, {SubnetId: aws.String("subnet-2")}}},
+	})
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := r.Save(path); err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	replayed, err := c.OcmCluster()
+	if err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	cfg, err := c.AwsConfig(replayed.Region().ID())
+	if err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	m, err := mirrosa.NewRosaClientFromCluster(context.TODO(), slog.New(slog.NewTextHandler(os.Stdout, nil)), replayed, cfg, mirrosa.Options{})
+	if err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	if m.ClusterInfo.VpcId != "vpc-1" || !reflect.DeepEqual(m.ClusterInfo.SubnetIds, []string{"subnet-1", "subnet-2"}) {
+		t.Errorf("expected vpc-1 with subnet-1 and subnet-2, got %+v", *m.ClusterInfo)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		expectErr bool
+	}{
+		{
+			name: "current version",
+			data: `{"version": 1, "clusterId": "mock", "interactions": []}`,
+		},
+		{
+			name:      "unsupported version",
+			data:      `{"version": 2, "clusterId": "mock", "interactions": []}`,
+			expectErr: true,
+		},
+		{
+			name:      "invalid",
+			data:      `{`,
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cassette.json")
+			if err := os.WriteFile(path, []byte(test.data), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := Load(path)
+			if (err != nil) != test.expectErr {
+				t.Errorf("expected err: %v, got %v", test.expectErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Package evidence collects the raw AWS API responses, OCM cluster object, and final report that mirrosa based its
+// verdict on, so that the verdict can be audited after the cluster has changed
+package evidence
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+	"github.com/mjlshen/mirrosa/pkg/redact"
+	"github.com/mjlshen/mirrosa/pkg/report"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// Bundle collects every AWS API call observed during a run. Its Observe method is a mirrosa.AwsObserver.
+type Bundle struct {
+	// Redactor, if set, pseudonymizes every file in the Bundle so that it can be shared
+	Redactor *redact.Redactor
+
+	mu        sync.Mutex
+	exchanges []exchange
+	err       error
+}
+
+// exchange is the JSON form of a mirrosa.AwsExchange
+type exchange struct {
+	Service   string          `json:"service"`
+	Operation string          `json:"operation"`
+	Started   time.Time       `json:"startTime"`
+	Duration  float64         `json:"durationSeconds"`
+	Input     json.RawMessage `json:"input"`
+	Output    json.RawMessage `json:"output,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// manifest describes the contents of a Bundle
+type manifest struct {
+	Cluster   mirrosa.ClusterInfo `json:"cluster"`
+	Created   time.Time           `json:"created"`
+	Exchanges []string            `json:"exchanges"`
+}
+
+// Observe records an AWS API call, it is safe for concurrent use. The call is marshaled immediately because
+// paginators reuse and modify the same input between pages.
+func (b *Bundle) Observe(e mirrosa.AwsExchange) {
+	out := exchange{
+		Service:   e.Service,
+		Operation: e.Operation,
+		Started:   e.Started,
+		Duration:  e.Duration.Seconds(),
+	}
+
+	input, err := json.Marshal(e.Input)
+	if err != nil {
+		b.fail(fmt.Errorf("failed to marshal %s:%s input: %w", e.Service, e.Operation, err))
+		return
+	}
+	out.Input = input
+
+	if e.Err != nil {
+		out.Error = e.Err.Error()
+	} else {
+		output, err := json.Marshal(e.Output)
+		if err != nil {
+			b.fail(fmt.Errorf("failed to marshal %s:%s output: %w", e.Service, e.Operation, err))
+			return
+		}
+		out.Output = output
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.exchanges = append(b.exchanges, out)
+}
+
+// fail remembers the first error while observing, which is returned when the Bundle is written
+func (b *Bundle) fail(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// file is a single file in a Bundle
+type file struct {
+	name string
+	data []byte
+}
+
+// files renders the Bundle, the OCM cluster object, and the final report as files:
+//
+//	manifest.json           the cluster, when the bundle was created, and the list of AWS API calls
+//	cluster.json            the cluster object from OCM
+//	report.json             the final report, in the same format as --output json
+//	aws/0001-EC2-DescribeVpcs.json, ...
+//	                        every AWS API call in the order they were made, with its input and output
+func (b *Bundle) files(cluster *cmv1.Cluster, r mirrosa.Report) ([]file, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	var files []file
+	m := manifest{
+		Cluster:   r.Cluster,
+		Created:   time.Now().UTC(),
+		Exchanges: []string{},
+	}
+
+	for i, e := range b.exchanges {
+		data, err := json.MarshalIndent(e, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s:%s: %w", e.Service, e.Operation, err)
+		}
+
+		name := fmt.Sprintf("aws/%04d-%s-%s.json", i+1, e.Service, e.Operation)
+		m.Exchanges = append(m.Exchanges, name)
+		files = append(files, file{name: name, data: data})
+	}
+
+	if cluster != nil {
+		var buf bytes.Buffer
+		if err := cmv1.MarshalCluster(cluster, &buf); err != nil {
+			return nil, fmt.Errorf("failed to marshal cluster: %w", err)
+		}
+		files = append(files, file{name: "cluster.json", data: buf.Bytes()})
+	}
+
+	var buf bytes.Buffer
+	if err := report.JSON(&buf, r); err != nil {
+		return nil, fmt.Errorf("failed to render report: %w", err)
+	}
+	files = append(files, file{name: "report.json", data: buf.Bytes()})
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	files = append([]file{{name: "manifest.json", data: data}}, files...)
+	for i := range files {
+		files[i].data = b.Redactor.Bytes(files[i].data)
+	}
+
+	return files, nil
+}
+
+// WriteDir writes the Bundle, the OCM cluster object, and the final report to dir, which is created if needed
+func (b *Bundle) WriteDir(dir string, cluster *cmv1.Cluster, r mirrosa.Report) error {
+	files, err := b.files(cluster, r)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		path := filepath.Join(dir, filepath.FromSlash(f.name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(path, f.data, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteTarball writes the Bundle, the OCM cluster object, and the final report to a gzipped tarball at path
+func (b *Bundle) WriteTarball(path string, cluster *cmv1.Cluster, r mirrosa.Report) error {
+	files, err := b.files(cluster, r)
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+
+	// Every file is nested under a directory named after the cluster so that extracting it is tidy
+	root := fmt.Sprintf("mirrosa-evidence-%s", b.Redactor.String(r.Cluster.Name))
+	modTime := time.Now()
+	for _, f := range files {
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    root + "/" + f.name,
+			Mode:    0644,
+			Size:    int64(len(f.data)),
+			ModTime: modTime,
+		}); err != nil {
+			return err
+		}
+
+		if _, err := tw.Write(f.data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	if err := gw.Close(); err != nil {
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,213 @@
+package evidence
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+	"github.com/mjlshen/mirrosa/pkg/redact"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// mockBundle returns a Bundle with a successful and a failed AWS API call
+func mockBundle() *Bundle {
+	b := &Bundle{}
+	b.Observe(mirrosa.AwsExchange{
+		Service:   "EC2",
+		Operation: "DescribeVpcs",
+		Started:   time.Now(),
+		Input:     &ec2.DescribeVpcsInput{VpcIds: []string{"vpc-1"}},
+		Output:    &ec2.DescribeVpcsOutput{Vpcs: []types.Vpc{{VpcId: aws.String("vpc-1")}}},
+	})
+	b.Observe(mirrosa.AwsExchange{
+		Service:   "EC2",
+		Operation: "DescribeDhcpOptions",
+		Started:   time.Now(),
+		Input:     &ec2.DescribeDhcpOptionsInput{},
+		Err:       errors.New("api error"),
+	})
+
+	return b
+}
+
+func mockCluster(t *testing.T) *cmv1.Cluster {
+	cluster, err := cmv1.NewCluster().ID("mock-id").Name("mock").Build()
+	if err != nil {
+		t.Fatalf("failed to build cluster: %v", err)
+	}
+
+	return cluster
+}
+
+var expectedFiles = []string{
+	"aws/0001-EC2-DescribeVpcs.json",
+	"aws/0002-EC2-DescribeDhcpOptions.json",
+	"cluster.json",
+	"manifest.json",
+	"report.json",
+}
+
+func TestBundle_WriteDir(t *testing.T) {
+	dir := t.TempDir()
+	r := mirrosa.Report{Cluster: mirrosa.ClusterInfo{Name: "mock"}}
+	if err := mockBundle().WriteDir(dir, mockCluster(t), r); err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	for _, name := range expectedFiles {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s to be written, got %v", name, err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "aws", "0001-EC2-DescribeVpcs.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var e struct {
+		Output ec2.DescribeVpcsOutput `json:"output"`
+	}
+	if err := json.Unmarshal(data, &e); err != nil {
+		t.Fatalf("expected valid JSON, got %v", err)
+	}
+
+	if len(e.Output.Vpcs) != 1 || aws.ToString(e.Output.Vpcs[0].VpcId) != "vpc-1" {
+		t.Errorf("expected the raw DescribeVpcs output, got %s", data)
+	}
+
+	data, err = os.ReadFile(filepath.Join(dir, "aws", "0002-EC2-DescribeDhcpOptions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), `"error": "api error"`) {
+		t.Errorf("expected the error of a failed call, got %s", data)
+	}
+}
+
+func TestBundle_ObservePaginatedInput(t *testing.T) {
+	// Paginators reuse the same input, only changing its NextToken between pages
+	input := &ec2.DescribeInstancesInput{}
+	b := &Bundle{}
+	for _, token := range []*string{nil, aws.String("page-2")} {
+		input.NextToken = token
+		b.Observe(mirrosa.AwsExchange{
+			Service:   "EC2",
+			Operation: "DescribeInstances",
+			Started:   time.Now(),
+			Input:     input,
+			Output:    &ec2.DescribeInstancesOutput{},
+		})
+	}
+
+	dir := t.TempDir()
+	if err := b.WriteDir(dir, nil, mirrosa.Report{}); err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	for name, expected := range map[string]string{
+		"aws/0001-EC2-DescribeInstances.json": "",
+		"aws/0002-EC2-DescribeInstances.json": "page-2",
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var e struct {
+			Input ec2.DescribeInstancesInput `json:"input"`
+		}
+		if err := json.Unmarshal(data, &e); err != nil {
+			t.Fatalf("expected valid JSON, got %v", err)
+		}
+
+		if actual := aws.ToString(e.Input.NextToken); actual != expected {
+			t.Errorf("expected %s to have NextToken %q, got %q", name, expected, actual)
+		}
+	}
+}
+
+func TestBundle_WriteTarball(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "evidence.tar.gz")
+	r := mirrosa.Report{Cluster: mirrosa.ClusterInfo{Name: "mock"}}
+	if err := mockBundle().WriteTarball(path, mockCluster(t), r); err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("expected a gzipped tarball, got %v", err)
+	}
+
+	var names []string
+	tr := tar.NewReader(gr)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, strings.TrimPrefix(h.Name, "mirrosa-evidence-mock/"))
+	}
+
+	slices.Sort(names)
+	if !slices.Equal(names, expectedFiles) {
+		t.Errorf("expected %v, got %v", expectedFiles, names)
+	}
+}
+
+func TestBundle_Redactor(t *testing.T) {
+	redactor, err := redact.New("mock-key", "mock-cluster")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := &Bundle{Redactor: redactor}
+	b.Observe(mirrosa.AwsExchange{
+		Service:   "EC2",
+		Operation: "DescribeVpcs",
+		Started:   time.Now(),
+		Input:     &ec2.DescribeVpcsInput{},
+		Output:    &ec2.DescribeVpcsOutput{Vpcs: []types.Vpc{{VpcId: aws.String("vpc-1"), OwnerId: aws.String("123456789012")}}},
+	})
+
+	dir := t.TempDir()
+	r := mirrosa.Report{Cluster: mirrosa.ClusterInfo{Name: "mock-cluster", AccountId: "123456789012"}}
+	if err := b.WriteDir(dir, nil, r); err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	for _, name := range []string{"aws/0001-EC2-DescribeVpcs.json", "manifest.json", "report.json"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, s := range []string{"123456789012", "mock-cluster"} {
+			if strings.Contains(string(data), s) {
+				t.Errorf("expected %s to be redacted in %s, got %s", s, name, data)
+			}
+		}
+	}
+}
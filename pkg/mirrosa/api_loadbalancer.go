@@ -2,8 +2,12 @@ package mirrosa
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
@@ -21,6 +25,14 @@ const (
 		"\n  - An internal (-int) NLB to balance traffic within the cluster's VPC."
 )
 
+const (
+	networkLoadBalancerName = "api-load-balancers"
+
+	apiLoadBalancerExistsCheckId         = "MIRROSA-NLB-001"
+	apiLoadBalancerListenerCheckId       = "MIRROSA-NLB-002"
+	apiLoadBalancerHealthyTargetsCheckId = "MIRROSA-NLB-003"
+)
+
 // elb represents the expected state of an Elastic Load Balancer in AWS
 type elb struct {
 	name              string
@@ -57,23 +69,30 @@ type NetworkLoadBalancer struct {
 
 func (c *Client) NewApiLoadBalancer() NetworkLoadBalancer {
 	return NetworkLoadBalancer{
-		log:         c.log,
+		log:         c.log.With(slog.String("component", networkLoadBalancerName)),
 		InfraName:   c.ClusterInfo.InfraName,
 		PrivateLink: c.Cluster.AWS().PrivateLink(),
 		Sts:         c.Cluster.AWS().STS() != nil,
 		VpcId:       c.ClusterInfo.VpcId,
-		ElbV2Client: elbv2.NewFromConfig(c.AwsConfig),
+		ElbV2Client: c.awsClients().elbv2,
 	}
 }
 
 func (n NetworkLoadBalancer) Validate(ctx context.Context) error {
-	for name, nlb := range n.getExpectedNLBs() {
+	var errs []error
+	expectedNLBs := n.getExpectedNLBs()
+	for _, name := range slices.Sorted(maps.Keys(expectedNLBs)) {
+		nlb := expectedNLBs[name]
 		n.log.Info("searching for network load balancer", slog.String("name", nlb.name))
 		resp, err := n.ElbV2Client.DescribeLoadBalancers(ctx, &elbv2.DescribeLoadBalancersInput{
 			Names: []string{nlb.name},
 		})
 		if err != nil {
-			return err
+			// Looking up a load balancer by name fails instead of returning an empty list when it does not exist
+			if !isAwsErrorCode(err, "LoadBalancerNotFound") {
+				return errors.Join(append(errs, err)...)
+			}
+			resp = &elbv2.DescribeLoadBalancersOutput{}
 		}
 
 		var (
@@ -90,19 +109,41 @@ func (n NetworkLoadBalancer) Validate(ctx context.Context) error {
 
 		switch len(matches) {
 		case 0:
-			return fmt.Errorf("NLB %s not found in VPC: %s", nlb.name, n.VpcId)
+			errs = append(errs, Finding{
+				CheckId:    apiLoadBalancerExistsCheckId,
+				ResourceId: nlb.name,
+				Expected:   fmt.Sprintf("1 network load balancer in %s", n.VpcId),
+				Actual:     "0 network load balancers",
+				Severity:   SeverityError,
+				Message:    fmt.Sprintf("NLB %s not found in VPC: %s", nlb.name, n.VpcId),
+			})
+			continue
 		case 1:
 			n.log.Info("found NLB", slog.String("arn", matches[0]))
 			nlbArn = matches[0]
+			recordResource(ctx, Resource{
+				Type: ResourceTypeLoadBalancer,
+				Id:   nlbArn,
+				Name: nlb.name,
+				Role: strings.TrimPrefix(name, "api-"),
+			})
 		default:
-			return fmt.Errorf("multiple matches found for NLB: %s in VPC %s", nlb.name, n.VpcId)
+			errs = append(errs, Finding{
+				CheckId:    apiLoadBalancerExistsCheckId,
+				ResourceId: nlb.name,
+				Expected:   fmt.Sprintf("1 network load balancer in %s", n.VpcId),
+				Actual:     fmt.Sprintf("%d network load balancers", len(matches)),
+				Severity:   SeverityError,
+				Message:    fmt.Sprintf("multiple matches found for NLB: %s in VPC %s", nlb.name, n.VpcId),
+			})
+			continue
 		}
 
 		listenResp, err := n.ElbV2Client.DescribeListeners(ctx, &elbv2.DescribeListenersInput{
 			LoadBalancerArn: aws.String(nlbArn),
 		})
 		if err != nil {
-			return err
+			return errors.Join(append(errs, err)...)
 		}
 
 		for _, l := range listenResp.Listeners {
@@ -114,21 +155,53 @@ func (n NetworkLoadBalancer) Validate(ctx context.Context) error {
 			for k, expectedListener := range nlb.expectedListeners {
 				if listenersEqual(expectedListener, l) {
 					if err := n.validateTargetGroups(ctx, *l.DefaultActions[0].TargetGroupArn, expectedListener.healthyTargets); err != nil {
-						return err
+						errs = append(errs, err)
+					} else {
+						n.log.Info("listener validated", slog.String("elb", k), slog.String("listenerConfig", fmt.Sprintf("%+v", expectedListener)))
 					}
-
-					n.log.Info("listener validated", slog.String("elb", k), slog.String("listenerConfig", fmt.Sprintf("%+v", expectedListener)))
 					delete(nlb.expectedListeners, k)
 				}
 			}
 		}
 
-		if len(nlb.expectedListeners) > 0 {
-			return fmt.Errorf("missing required listeners in NLB %s: %v", name, nlb.expectedListeners)
+		for _, k := range slices.Sorted(maps.Keys(nlb.expectedListeners)) {
+			missing := nlb.expectedListeners[k]
+			errs = append(errs, Finding{
+				CheckId:    apiLoadBalancerListenerCheckId,
+				ResourceId: nlbArn,
+				Expected:   fmt.Sprintf("%s listener on port %d", missing.protocol, missing.port),
+				Actual:     "no matching listener",
+				Severity:   SeverityError,
+				Message:    fmt.Sprintf("missing required %s listener in NLB %s", k, name),
+			})
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+func (n NetworkLoadBalancer) Name() string {
+	return networkLoadBalancerName
+}
+
+func (n NetworkLoadBalancer) Dependencies() []string {
+	return []string{privateHostedZoneName, vpcName}
+}
+
+func (n NetworkLoadBalancer) Checks() []Check {
+	return []Check{
+		{Id: apiLoadBalancerExistsCheckId, Title: "API network load balancers exist"},
+		{
+			Id:       apiLoadBalancerListenerCheckId,
+			Title:    "API network load balancers have the required listeners",
+			Requires: []string{apiLoadBalancerExistsCheckId},
+		},
+		{
+			Id:       apiLoadBalancerHealthyTargetsCheckId,
+			Title:    "Listener target groups have the expected number of healthy targets",
+			Requires: []string{apiLoadBalancerListenerCheckId},
+		},
+	}
 }
 
 func (n NetworkLoadBalancer) Description() string {
@@ -197,16 +270,38 @@ func (n NetworkLoadBalancer) validateTargetGroups(ctx context.Context, arn strin
 		TargetGroupArns: []string{arn},
 	})
 	if err != nil {
-		return fmt.Errorf("failed to find target group %s: %w", arn, err)
+		if !isAwsErrorCode(err, "TargetGroupNotFound") {
+			return fmt.Errorf("failed to find target group %s: %w", arn, err)
+		}
+		resp = &elbv2.DescribeTargetGroupsOutput{}
 	}
 
 	switch len(resp.TargetGroups) {
 	case 0:
-		return fmt.Errorf("target group %s not found", arn)
+		return Finding{
+			CheckId:    apiLoadBalancerHealthyTargetsCheckId,
+			ResourceId: arn,
+			Expected:   "1 target group",
+			Actual:     "0 target groups",
+			Severity:   SeverityError,
+			Message:    fmt.Sprintf("target group %s not found", arn),
+		}
 	case 1:
 		n.log.Debug("found target group", slog.String("arn", *resp.TargetGroups[0].TargetGroupArn))
+		recordResource(ctx, Resource{
+			Type: ResourceTypeTargetGroup,
+			Id:   *resp.TargetGroups[0].TargetGroupArn,
+			Name: aws.ToString(resp.TargetGroups[0].TargetGroupName),
+		})
 	default:
-		return fmt.Errorf("multiple matches found for target group: %s", arn)
+		return Finding{
+			CheckId:    apiLoadBalancerHealthyTargetsCheckId,
+			ResourceId: arn,
+			Expected:   "1 target group",
+			Actual:     fmt.Sprintf("%d target groups", len(resp.TargetGroups)),
+			Severity:   SeverityError,
+			Message:    fmt.Sprintf("multiple matches found for target group: %s", arn),
+		}
 	}
 
 	n.log.Debug("validating target group: %s has %d healthy targets", slog.String("arn", arn), slog.Int("healthyTargets", expected))
@@ -225,7 +320,14 @@ func (n NetworkLoadBalancer) validateTargetGroups(ctx context.Context, arn strin
 	}
 
 	if healthyTargets != expected {
-		return fmt.Errorf("expected %d healthy targets for %s, only found %d", expected, arn, healthyTargets)
+		return Finding{
+			CheckId:    apiLoadBalancerHealthyTargetsCheckId,
+			ResourceId: arn,
+			Expected:   fmt.Sprintf("%d healthy targets", expected),
+			Actual:     fmt.Sprintf("%d healthy targets", healthyTargets),
+			Severity:   SeverityError,
+			Message:    fmt.Sprintf("expected %d healthy targets for %s, only found %d", expected, arn, healthyTargets),
+		}
 	}
 
 	n.log.Info("validated target group", slog.String("arn", arn))
@@ -0,0 +1,131 @@
+package mirrosa
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	"github.com/aws/smithy-go"
+)
+
+type mockNetworkLoadBalancerAPIClient struct {
+	describeLoadBalancersErr error
+	describeTargetGroupsErr  error
+}
+
+func (m mockNetworkLoadBalancerAPIClient) DescribeLoadBalancers(ctx context.Context, params *elbv2.DescribeLoadBalancersInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeLoadBalancersOutput, error) {
+	if m.describeLoadBalancersErr != nil {
+		return nil, m.describeLoadBalancersErr
+	}
+
+	return &elbv2.DescribeLoadBalancersOutput{
+		LoadBalancers: []types.LoadBalancer{{
+			LoadBalancerArn:  aws.String("arn:mock-int"),
+			LoadBalancerName: aws.String(params.Names[0]),
+			Type:             types.LoadBalancerTypeEnumNetwork,
+			VpcId:            aws.String("vpc-1"),
+		}},
+	}, nil
+}
+
+func (m mockNetworkLoadBalancerAPIClient) DescribeListeners(ctx context.Context, params *elbv2.DescribeListenersInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeListenersOutput, error) {
+	return &elbv2.DescribeListenersOutput{
+		Listeners: []types.Listener{
+			{
+				ListenerArn:    aws.String("arn:etcd"),
+				Port:           aws.Int32(22623),
+				Protocol:       types.ProtocolEnumTcp,
+				DefaultActions: []types.Action{{TargetGroupArn: aws.String("arn:tg-etcd")}},
+			},
+			{
+				ListenerArn:    aws.String("arn:kube-apiserver"),
+				Port:           aws.Int32(6443),
+				Protocol:       types.ProtocolEnumTcp,
+				DefaultActions: []types.Action{{TargetGroupArn: aws.String("arn:tg-kube-apiserver")}},
+			},
+		},
+	}, nil
+}
+
+func (m mockNetworkLoadBalancerAPIClient) DescribeTargetGroups(ctx context.Context, params *elbv2.DescribeTargetGroupsInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeTargetGroupsOutput, error) {
+	if m.describeTargetGroupsErr != nil {
+		return nil, m.describeTargetGroupsErr
+	}
+
+	return &elbv2.DescribeTargetGroupsOutput{
+		TargetGroups: []types.TargetGroup{{TargetGroupArn: aws.String(params.TargetGroupArns[0])}},
+	}, nil
+}
+
+func (m mockNetworkLoadBalancerAPIClient) DescribeTargetHealth(ctx context.Context, params *elbv2.DescribeTargetHealthInput, optFns ...func(*elbv2.Options)) (*elbv2.DescribeTargetHealthOutput, error) {
+	healthy := types.TargetHealthDescription{TargetHealth: &types.TargetHealth{State: types.TargetHealthStateEnumHealthy}}
+	return &elbv2.DescribeTargetHealthOutput{
+		TargetHealthDescriptions: []types.TargetHealthDescription{healthy, healthy, healthy},
+	}, nil
+}
+
+func TestNetworkLoadBalancer_Validate(t *testing.T) {
+	tests := []struct {
+		name           string
+		client         mockNetworkLoadBalancerAPIClient
+		expected       []string
+		expectedApiErr bool
+	}{
+		{
+			name:   "healthy",
+			client: mockNetworkLoadBalancerAPIClient{},
+		},
+		{
+			name: "deleted load balancer",
+			client: mockNetworkLoadBalancerAPIClient{
+				describeLoadBalancersErr: &smithy.GenericAPIError{Code: "LoadBalancerNotFound"},
+			},
+			expected: []string{apiLoadBalancerExistsCheckId},
+		},
+		{
+			name: "deleted target groups",
+			client: mockNetworkLoadBalancerAPIClient{
+				describeTargetGroupsErr: &smithy.GenericAPIError{Code: "TargetGroupNotFound"},
+			},
+			expected: []string{apiLoadBalancerHealthyTargetsCheckId, apiLoadBalancerHealthyTargetsCheckId},
+		},
+		{
+			name: "api error",
+			client: mockNetworkLoadBalancerAPIClient{
+				describeLoadBalancersErr: errors.New("api error"),
+			},
+			expectedApiErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			n := NetworkLoadBalancer{
+				log:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+				InfraName:   "mock",
+				PrivateLink: true,
+				VpcId:       "vpc-1",
+				ElbV2Client: test.client,
+			}
+
+			findings, err := splitFindings(n.Validate(context.TODO()))
+			var checkIds []string
+			for _, f := range findings {
+				checkIds = append(checkIds, f.CheckId)
+			}
+			if !reflect.DeepEqual(checkIds, test.expected) {
+				t.Errorf("expected findings %v, got %v", test.expected, checkIds)
+			}
+
+			if (err != nil) != test.expectedApiErr {
+				t.Errorf("expected api error %t, got %v", test.expectedApiErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package mirrosa
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+)
+
+// arnResourcePrefixes maps the prefix of an AWS resource ID to the service and resource type of its ARN
+var arnResourcePrefixes = []struct {
+	prefix       string
+	service      string
+	resourceType string
+}{
+	// vpce-svc- must come before any shorter prefix it shares
+	{prefix: "vpce-svc-", service: "ec2", resourceType: "vpc-endpoint-service"},
+	{prefix: "vpc-", service: "ec2", resourceType: "vpc"},
+	{prefix: "sg-", service: "ec2", resourceType: "security-group"},
+	{prefix: "sgr-", service: "ec2", resourceType: "security-group-rule"},
+	{prefix: "i-", service: "ec2", resourceType: "instance"},
+	{prefix: "dopt-", service: "ec2", resourceType: "dhcp-options"},
+	{prefix: "subnet-", service: "ec2", resourceType: "subnet"},
+}
+
+// ResourceArn returns the ARN of the AWS resource identified by id in the cluster's account and region, or an empty
+// string if id isn't an ID mirrosa knows how to convert, e.g. because it's the name of a resource that wasn't found.
+// ARNs are returned unchanged.
+func (c ClusterInfo) ResourceArn(id string) string {
+	if arn.IsARN(id) {
+		return id
+	}
+
+	partition := awsPartition(c.Region)
+
+	// Route53 hosted zone IDs are returned as /hostedzone/ID by the AWS API and are global
+	if zoneId, ok := strings.CutPrefix(id, "/hostedzone/"); ok {
+		return arn.ARN{Partition: partition, Service: "route53", Resource: "hostedzone/" + zoneId}.String()
+	}
+
+	if c.AccountId == "" || c.Region == "" {
+		return ""
+	}
+
+	for _, p := range arnResourcePrefixes {
+		if strings.HasPrefix(id, p.prefix) {
+			return arn.ARN{
+				Partition: partition,
+				Service:   p.service,
+				Region:    c.Region,
+				AccountID: c.AccountId,
+				Resource:  p.resourceType + "/" + id,
+			}.String()
+		}
+	}
+
+	return ""
+}
+
+// awsPartition returns the AWS partition that region belongs to
+func awsPartition(region string) string {
+	switch {
+	case strings.HasPrefix(region, "us-gov-"):
+		return "aws-us-gov"
+	case strings.HasPrefix(region, "cn-"):
+		return "aws-cn"
+	default:
+		return "aws"
+	}
+}
@@ -0,0 +1,58 @@
+package mirrosa
+
+import "testing"
+
+func TestClusterInfo_ResourceArn(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     ClusterInfo
+		id       string
+		expected string
+	}{
+		{
+			name:     "vpc",
+			info:     ClusterInfo{AccountId: "123456789012", Region: "us-east-1"},
+			id:       "vpc-0123456789abcdef0",
+			expected: "arn:aws:ec2:us-east-1:123456789012:vpc/vpc-0123456789abcdef0",
+		},
+		{
+			name:     "vpc endpoint service",
+			info:     ClusterInfo{AccountId: "123456789012", Region: "us-east-1"},
+			id:       "vpce-svc-0123456789abcdef0",
+			expected: "arn:aws:ec2:us-east-1:123456789012:vpc-endpoint-service/vpce-svc-0123456789abcdef0",
+		},
+		{
+			name:     "govcloud instance",
+			info:     ClusterInfo{AccountId: "123456789012", Region: "us-gov-west-1"},
+			id:       "i-0123456789abcdef0",
+			expected: "arn:aws-us-gov:ec2:us-gov-west-1:123456789012:instance/i-0123456789abcdef0",
+		},
+		{
+			name:     "hosted zone",
+			id:       "/hostedzone/Z0123456789ABCDEFGHIJ",
+			expected: "arn:aws:route53:::hostedzone/Z0123456789ABCDEFGHIJ",
+		},
+		{
+			name:     "arn",
+			id:       "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/mock-int/0123456789abcdef",
+			expected: "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/mock-int/0123456789abcdef",
+		},
+		{
+			name: "unknown account",
+			id:   "sg-0123456789abcdef0",
+		},
+		{
+			name: "name",
+			info: ClusterInfo{AccountId: "123456789012", Region: "us-east-1"},
+			id:   "mock-master-sg",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := test.info.ResourceArn(test.id); actual != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
@@ -2,8 +2,17 @@ package mirrosa
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
 )
 
 type Ec2AwsApi interface {
@@ -13,3 +22,147 @@ type Ec2AwsApi interface {
 	ec2.DescribeSubnetsAPIClient
 	ec2.DescribeVpcsAPIClient
 }
+
+const (
+	// awsMaxAttempts is the maximum number of attempts of each AWS API call, large accounts are frequently throttled
+	awsMaxAttempts = 10
+
+	// awsMaxBackoffDelay is the maximum delay between attempts of an AWS API call
+	awsMaxBackoffDelay = 20 * time.Second
+)
+
+// awsClients are the AWS API clients shared by every component in a run. Read-only calls made through them are
+// memoized by a describeCache, so components see a consistent snapshot of the account, and every call is counted
+// by an apiCallRecorder.
+type awsClients struct {
+	ec2     *ec2.Client
+	elbv2   *elbv2.Client
+	route53 *route53.Client
+
+	recorder *apiCallRecorder
+}
+
+func newAwsClients(cfg aws.Config, opts Options) *awsClients {
+	recorder := newApiCallRecorder()
+
+	cfg = cfg.Copy()
+	cfg.Retryer = newAwsRetryer
+	cfg.APIOptions = append(cfg.APIOptions, recorder.addToStack)
+	if opts.AwsObserver != nil {
+		cfg.APIOptions = append(cfg.APIOptions, opts.AwsObserver.addToStack)
+	}
+	// Each is added to the start of the stack, so the cache runs first and only calls that miss it are recorded and
+	// observed
+	cfg.APIOptions = append(cfg.APIOptions, newDescribeCache(recorder).addToStack)
+
+	endpoints := opts.AwsEndpoints
+	return &awsClients{
+		ec2: ec2.NewFromConfig(cfg, func(o *ec2.Options) {
+			o.BaseEndpoint = endpoints.override(o.BaseEndpoint, endpoints.Ec2)
+		}),
+		elbv2: elbv2.NewFromConfig(cfg, func(o *elbv2.Options) {
+			o.BaseEndpoint = endpoints.override(o.BaseEndpoint, endpoints.ElbV2)
+		}),
+		route53: route53.NewFromConfig(cfg, func(o *route53.Options) {
+			o.BaseEndpoint = endpoints.override(o.BaseEndpoint, endpoints.Route53)
+		}),
+		recorder: recorder,
+	}
+}
+
+// AwsEndpoints override the endpoint URLs of the AWS APIs that mirrosa calls, e.g. to run against a LocalStack or
+// moto server. Requests are still signed for the cluster's region, and empty endpoints are resolved from it as usual.
+type AwsEndpoints struct {
+	// Ec2 is the endpoint URL of the EC2 API
+	Ec2 string
+
+	// ElbV2 is the endpoint URL of the Elastic Load Balancing v2 API
+	ElbV2 string
+
+	// Route53 is the endpoint URL of the Route 53 API
+	Route53 string
+}
+
+// override returns endpoint if it is set, otherwise the client's current base endpoint
+func (AwsEndpoints) override(current *string, endpoint string) *string {
+	if endpoint == "" {
+		return current
+	}
+
+	return aws.String(endpoint)
+}
+
+// newAwsRetryer retries throttled and other transient errors with exponential backoff. The client-side retry quota
+// is disabled because mirrosa is short-lived and would otherwise give up on throttled calls too soon.
+func newAwsRetryer() aws.Retryer {
+	retryer := retry.NewStandard(func(o *retry.StandardOptions) {
+		o.RateLimiter = ratelimit.None
+	})
+
+	return retry.AddWithMaxBackoffDelay(retry.AddWithMaxAttempts(retryer, awsMaxAttempts), awsMaxBackoffDelay)
+}
+
+// awsClients returns the AWS API clients shared by every component, building them from c.AwsConfig the first time
+func (c *Client) awsClients() *awsClients {
+	if c.aws == nil {
+		c.aws = newAwsClients(c.AwsConfig, c.opts)
+	}
+
+	return c.aws
+}
+
+// AwsExchange is a single AWS API call that was sent to AWS and its outcome
+type AwsExchange struct {
+	// Service is the AWS service ID, e.g. EC2
+	Service string
+
+	// Operation is the name of the AWS API operation, e.g. DescribeVpcs
+	Operation string
+
+	// Started is when the call was made
+	Started time.Time
+
+	// Duration is how long the call took, including any retries
+	Duration time.Duration
+
+	// Input is the input of the call, e.g. *ec2.DescribeVpcsInput
+	Input interface{}
+
+	// Output is the output of the call, e.g. *ec2.DescribeVpcsOutput, which is nil if the call failed
+	Output interface{}
+
+	// Err is the error returned by the call, if any
+	Err error
+}
+
+// AwsObserver is called with every AWS API call made by mirrosa's components after it completes. Calls answered by
+// mirrosa's cache are not observed again. It may be called concurrently.
+type AwsObserver func(AwsExchange)
+
+// addToStack adds the observer to the start of an AWS API client's middleware stack, for use in aws.Config.APIOptions
+func (o AwsObserver) addToStack(stack *middleware.Stack) error {
+	observe := middleware.InitializeMiddlewareFunc("MirrosaAwsObserver", func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+		started := time.Now()
+		out, metadata, err := next.HandleInitialize(ctx, in)
+		o(AwsExchange{
+			Service:   middleware.GetServiceID(ctx),
+			Operation: middleware.GetOperationName(ctx),
+			Started:   started,
+			Duration:  time.Since(started),
+			Input:     in.Parameters,
+			Output:    out.Result,
+			Err:       err,
+		})
+
+		return out, metadata, err
+	})
+
+	return stack.Initialize.Add(observe, middleware.Before)
+}
+
+// isAwsErrorCode returns true if err is an AWS API error with the given code, e.g. a describe call for a resource
+// that has been deleted
+func isAwsErrorCode(err error, code string) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == code
+}
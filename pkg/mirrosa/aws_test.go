@@ -0,0 +1,50 @@
+package mirrosa
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+)
+
+func TestNewAwsClients_Endpoints(t *testing.T) {
+	var authorization []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = append(authorization, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "text/xml")
+		if strings.Contains(r.URL.Path, "hostedzone") {
+			w.Write([]byte(`<ListHostedZonesByNameResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/"><HostedZones/><IsTruncated>false</IsTruncated><MaxItems>100</MaxItems></ListHostedZonesByNameResponse>`))
+			return
+		}
+		w.Write([]byte(`<DescribeVpcsResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><vpcSet/></DescribeVpcsResponse>`))
+	}))
+	defer server.Close()
+
+	// Without an override, these clients would call the real regional AWS endpoints
+	clients := newAwsClients(aws.Config{
+		Region:      "eu-west-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "secret", ""),
+	}, Options{AwsEndpoints: AwsEndpoints{Ec2: server.URL, Route53: server.URL}})
+
+	if _, err := clients.ec2.DescribeVpcs(context.TODO(), &ec2.DescribeVpcsInput{}); err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	if _, err := clients.route53.ListHostedZonesByName(context.TODO(), &route53.ListHostedZonesByNameInput{}); err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	if len(authorization) != 2 {
+		t.Fatalf("expected both calls to reach the overridden endpoint, got %d", len(authorization))
+	}
+
+	if !strings.Contains(authorization[0], "/eu-west-1/ec2/") {
+		t.Errorf("expected the EC2 call to be signed for the cluster's region, got %s", authorization[0])
+	}
+}
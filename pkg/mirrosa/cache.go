@@ -0,0 +1,125 @@
+package mirrosa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aws/smithy-go/middleware"
+)
+
+// cachedOperationPrefixes are the prefixes of read-only AWS API operations that are safe to memoize
+var cachedOperationPrefixes = []string{"Describe", "Get", "List"}
+
+// describeCache memoizes read-only AWS API calls so that every component sees the same snapshot of an account and
+// identical calls are only made once per run, even when they are made concurrently
+type describeCache struct {
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+
+	// recorder, if set, counts calls that were answered from the cache
+	recorder *apiCallRecorder
+}
+
+// cacheEntry is the outcome of a single AWS API call, done is closed once out and err are set
+type cacheEntry struct {
+	done chan struct{}
+	out  middleware.InitializeOutput
+	err  error
+}
+
+func newDescribeCache(recorder *apiCallRecorder) *describeCache {
+	return &describeCache{
+		entries:  map[string]*cacheEntry{},
+		recorder: recorder,
+	}
+}
+
+// ID implements middleware.InitializeMiddleware
+func (d *describeCache) ID() string {
+	return "MirrosaDescribeCache"
+}
+
+// HandleInitialize implements middleware.InitializeMiddleware. It is added before any other middleware so that
+// cached calls are not signed, retried, or sent at all. Failed calls are not cached so that they can be retried, and
+// calls that were cancelled or timed out are not shared either, since that only says something about the caller's ctx.
+func (d *describeCache) HandleInitialize(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+	key, ok := cacheKey(ctx, in.Parameters)
+	if !ok {
+		return next.HandleInitialize(ctx, in)
+	}
+
+	for {
+		d.mu.Lock()
+		entry, ok := d.entries[key]
+		if !ok {
+			break
+		}
+		d.mu.Unlock()
+
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return middleware.InitializeOutput{}, middleware.Metadata{}, ctx.Err()
+		}
+
+		// The entry has already been dropped, so make the call again with this caller's own ctx
+		if isContextErr(entry.err) {
+			continue
+		}
+
+		if d.recorder != nil {
+			d.recorder.recordCached(ctx)
+		}
+		return entry.out, middleware.Metadata{}, entry.err
+	}
+
+	entry := &cacheEntry{done: make(chan struct{})}
+	d.entries[key] = entry
+	d.mu.Unlock()
+
+	out, metadata, err := next.HandleInitialize(ctx, in)
+	entry.out, entry.err = out, err
+	if err != nil {
+		d.mu.Lock()
+		delete(d.entries, key)
+		d.mu.Unlock()
+	}
+	close(entry.done)
+
+	return out, metadata, err
+}
+
+// isContextErr returns whether err is because a call's ctx was cancelled or timed out
+func isContextErr(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// cacheKey identifies an AWS API call by its service, operation, and input. Only read-only operations are cached.
+func cacheKey(ctx context.Context, params interface{}) (string, bool) {
+	operation := middleware.GetOperationName(ctx)
+	cacheable := false
+	for _, prefix := range cachedOperationPrefixes {
+		if strings.HasPrefix(operation, prefix) {
+			cacheable = true
+		}
+	}
+	if !cacheable {
+		return "", false
+	}
+
+	input, err := json.Marshal(params)
+	if err != nil {
+		return "", false
+	}
+
+	return fmt.Sprintf("%s/%s/%s", middleware.GetServiceID(ctx), operation, input), true
+}
+
+// addToStack adds the cache to the start of an AWS API client's middleware stack, for use in aws.Config.APIOptions
+func (d *describeCache) addToStack(stack *middleware.Stack) error {
+	return stack.Initialize.Add(d, middleware.Before)
+}
@@ -0,0 +1,120 @@
+package mirrosa
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/smithy-go/middleware"
+)
+
+func TestDescribeCache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/xml")
+		w.Write([]byte(`<DescribeVpcsResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><vpcSet/></DescribeVpcsResponse>`))
+	}))
+	defer server.Close()
+
+	var observed atomic.Int32
+	clients := newAwsClients(aws.Config{
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(server.URL),
+	}, Options{AwsObserver: func(AwsExchange) { observed.Add(1) }})
+
+	tests := []struct {
+		name     string
+		input    *ec2.DescribeVpcsInput
+		expected int32
+	}{
+		{
+			name:     "first call",
+			input:    &ec2.DescribeVpcsInput{VpcIds: []string{"vpc-1"}},
+			expected: 1,
+		},
+		{
+			name:     "identical call is cached",
+			input:    &ec2.DescribeVpcsInput{VpcIds: []string{"vpc-1"}},
+			expected: 1,
+		},
+		{
+			name:     "different input",
+			input:    &ec2.DescribeVpcsInput{VpcIds: []string{"vpc-2"}},
+			expected: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := clients.ec2.DescribeVpcs(context.TODO(), test.input); err != nil {
+				t.Fatalf("expected no err, got %v", err)
+			}
+
+			if n := requests.Load(); n != test.expected {
+				t.Errorf("expected %d requests, got %d", test.expected, n)
+			}
+
+			if n := observed.Load(); n != test.expected {
+				t.Errorf("expected %d observed calls, got %d", test.expected, n)
+			}
+		})
+	}
+}
+
+func TestDescribeCache_ContextErr(t *testing.T) {
+	cache := newDescribeCache(nil)
+	ctx := middleware.WithOperationName(middleware.WithServiceID(context.Background(), ec2.ServiceID), "DescribeVpcs")
+	in := middleware.InitializeInput{Parameters: &ec2.DescribeVpcsInput{VpcIds: []string{"vpc-1"}}}
+
+	var calls atomic.Int32
+	started := make(chan struct{})
+	next := middleware.InitializeHandlerFunc(func(ctx context.Context, in middleware.InitializeInput) (middleware.InitializeOutput, middleware.Metadata, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+			<-ctx.Done()
+			return middleware.InitializeOutput{}, middleware.Metadata{}, ctx.Err()
+		}
+		return middleware.InitializeOutput{Result: "ok"}, middleware.Metadata{}, nil
+	})
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	leaderErr := make(chan error)
+	go func() {
+		_, _, err := cache.HandleInitialize(leaderCtx, in, next)
+		leaderErr <- err
+	}()
+	<-started
+
+	waiter := make(chan middleware.InitializeOutput)
+	go func() {
+		out, _, err := cache.HandleInitialize(ctx, in, next)
+		if err != nil {
+			t.Errorf("expected no err for the waiting call, got %v", err)
+		}
+		waiter <- out
+	}()
+
+	// Give the second call time to start waiting for the first one before it is cancelled
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the cancelled call to return %v, got %v", context.Canceled, err)
+	}
+
+	if out := <-waiter; out.Result != "ok" {
+		t.Errorf("expected the waiting call to be made again, got %v", out.Result)
+	}
+
+	if n := calls.Load(); n != 2 {
+		t.Errorf("expected 2 calls, got %d", n)
+	}
+}
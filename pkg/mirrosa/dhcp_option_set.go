@@ -2,6 +2,7 @@ package mirrosa
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -19,6 +20,13 @@ const dhcpOptionsDescription = "DHCP Option Sets configure how devices uses the
 	"2. https://kubernetes.io/docs/concepts/overview/working-with-objects/names/#dns-subdomain-names\n" +
 	"3. https://github.com/coreos/bugs/issues/1934"
 
+const (
+	dhcpOptionsName = "dhcp-options"
+
+	dhcpOptionsLowercaseCheckId = "MIRROSA-DHCP-001"
+	dhcpOptionsNoSpacesCheckId  = "MIRROSA-DHCP-002"
+)
+
 // Ensure DhcpOptions implements Component
 var _ Component = &DhcpOptions{}
 
@@ -31,30 +39,38 @@ type DhcpOptions struct {
 	log   *slog.Logger
 	VpcId string
 
+	// DhcpOptionsId is the id of the DHCP Options Set attached to the VPC, which is looked up from VpcId if empty
+	DhcpOptionsId string
+
 	Ec2Client MirrosaDhcpOptionsAPIClient
 }
 
 func (c *Client) NewDhcpOptions() DhcpOptions {
 	return DhcpOptions{
-		log:       c.log,
-		VpcId:     c.ClusterInfo.VpcId,
-		Ec2Client: ec2.NewFromConfig(c.AwsConfig),
+		log:           c.log.With(slog.String("component", dhcpOptionsName)),
+		VpcId:         c.ClusterInfo.VpcId,
+		DhcpOptionsId: c.dhcpOptionsId,
+		Ec2Client:     c.awsClients().ec2,
 	}
 }
 
 func (d DhcpOptions) Validate(ctx context.Context) error {
 	d.log.Debug("validating that the attached DHCP Options Set has no uppercase characters in its domain name(s)")
-	vpcResp, err := d.Ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
-		VpcIds: []string{d.VpcId},
-	})
-	if err != nil {
-		return err
-	}
+	dhcpOptionsId := d.DhcpOptionsId
+	if dhcpOptionsId == "" {
+		vpcResp, err := d.Ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
+			VpcIds: []string{d.VpcId},
+		})
+		if err != nil {
+			return err
+		}
 
-	if len(vpcResp.Vpcs) != 1 {
-		return fmt.Errorf("unexpectedly received %d VPCs when describing: %s", len(vpcResp.Vpcs), d.VpcId)
+		if len(vpcResp.Vpcs) != 1 {
+			return fmt.Errorf("unexpectedly received %d VPCs when describing: %s", len(vpcResp.Vpcs), d.VpcId)
+		}
+		dhcpOptionsId = *vpcResp.Vpcs[0].DhcpOptionsId
 	}
-	dhcpOptionsId := *vpcResp.Vpcs[0].DhcpOptionsId
+	recordResource(ctx, Resource{Type: ResourceTypeDhcpOptions, Id: dhcpOptionsId})
 
 	dhcpResp, err := d.Ec2Client.DescribeDhcpOptions(ctx, &ec2.DescribeDhcpOptionsInput{
 		DhcpOptionsIds: []string{dhcpOptionsId},
@@ -67,15 +83,31 @@ func (d DhcpOptions) Validate(ctx context.Context) error {
 		return fmt.Errorf("unexepctedly received %d DHCP Options Sets when describing: %s", len(dhcpResp.DhcpOptions), dhcpOptionsId)
 	}
 
+	var errs []error
 	for _, config := range dhcpResp.DhcpOptions[0].DhcpConfigurations {
 		switch *config.Key {
 		case "domain-name":
 			for _, v := range config.Values {
 				d.log.Debug("validating DHCP Options Set domain name", slog.String("domainName", *v.Value))
 				if *v.Value != strings.ToLower(*v.Value) {
-					return fmt.Errorf("DHCP Options set: %s contains uppercase letters in the domain name: %s", dhcpOptionsId, *v.Value)
-				} else if strings.Contains(*v.Value, " ") {
-					return fmt.Errorf("DHCP Options set: %s contains a space in the domain name: %s", dhcpOptionsId, *v.Value)
+					errs = append(errs, Finding{
+						CheckId:    dhcpOptionsLowercaseCheckId,
+						ResourceId: dhcpOptionsId,
+						Expected:   "domain-name without uppercase letters",
+						Actual:     *v.Value,
+						Severity:   SeverityError,
+						Message:    fmt.Sprintf("DHCP Options set: %s contains uppercase letters in the domain name: %s", dhcpOptionsId, *v.Value),
+					})
+				}
+				if strings.Contains(*v.Value, " ") {
+					errs = append(errs, Finding{
+						CheckId:    dhcpOptionsNoSpacesCheckId,
+						ResourceId: dhcpOptionsId,
+						Expected:   "domain-name without spaces",
+						Actual:     *v.Value,
+						Severity:   SeverityError,
+						Message:    fmt.Sprintf("DHCP Options set: %s contains a space in the domain name: %s", dhcpOptionsId, *v.Value),
+					})
 				}
 			}
 		default:
@@ -84,7 +116,22 @@ func (d DhcpOptions) Validate(ctx context.Context) error {
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+func (d DhcpOptions) Name() string {
+	return dhcpOptionsName
+}
+
+func (d DhcpOptions) Dependencies() []string {
+	return []string{vpcName}
+}
+
+func (d DhcpOptions) Checks() []Check {
+	return []Check{
+		{Id: dhcpOptionsLowercaseCheckId, Title: "Domain name has no uppercase letters"},
+		{Id: dhcpOptionsNoSpacesCheckId, Title: "Domain name has no spaces"},
+	}
 }
 
 func (d DhcpOptions) Description() string {
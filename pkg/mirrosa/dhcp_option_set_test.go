@@ -2,6 +2,7 @@ package mirrosa
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"os"
 	"testing"
@@ -17,6 +18,9 @@ type mockMirrosaDhcpOptionsAPIClient struct {
 }
 
 func (m mockMirrosaDhcpOptionsAPIClient) DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(options *ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	if m.describeVpcsResp == nil {
+		return nil, errors.New("unexpected DescribeVpcs call")
+	}
 	return m.describeVpcsResp, nil
 }
 
@@ -135,6 +139,34 @@ func TestDhcpOptions_Validate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "dhcp options id already known",
+			dhcpOptions: &DhcpOptions{
+				log:           slog.New(slog.NewTextHandler(os.Stdout, nil)),
+				VpcId:         "id",
+				DhcpOptionsId: "dhcp-id",
+				Ec2Client: &mockMirrosaDhcpOptionsAPIClient{
+					describeDhcpOptionsResp: &ec2.DescribeDhcpOptionsOutput{
+						DhcpOptions: []types.DhcpOptions{
+							{
+								DhcpConfigurations: []types.DhcpConfiguration{
+									{
+										Key: aws.String("domain-name"),
+										Values: []types.AttributeValue{
+											{
+												Value: aws.String("ec2.internal"),
+											},
+										},
+									},
+								},
+								DhcpOptionsId: aws.String("dhcp-id"),
+							},
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,82 @@
+package mirrosa
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Severity describes how serious a Finding is
+type Severity string
+
+const (
+	// SeverityWarning is used for suspicious configuration that does not necessarily break a cluster
+	SeverityWarning Severity = "warning"
+
+	// SeverityError is used for configuration that breaks a cluster or leaves it unsupported
+	SeverityError Severity = "error"
+)
+
+// Finding is a single misconfiguration detected while validating a Component. It implements error so that
+// Component.Validate can return one directly, or several at once with errors.Join.
+type Finding struct {
+	// CheckId is the stable identifier of the check that produced the Finding, e.g. MIRROSA-VPC-001
+	CheckId string `json:"checkId"`
+
+	// ResourceId is the ID or ARN of the AWS resource the Finding is about
+	ResourceId string `json:"resourceId"`
+
+	// Expected describes the state the resource should be in
+	Expected string `json:"expected"`
+
+	// Actual describes the state the resource was observed in
+	Actual string `json:"actual"`
+
+	// Severity is how serious the Finding is
+	Severity Severity `json:"severity"`
+
+	// Message is a human-readable explanation of the Finding
+	Message string `json:"message"`
+}
+
+func (f Finding) Error() string {
+	return fmt.Sprintf("%s: %s", f.CheckId, f.Message)
+}
+
+// splitFindings separates the Findings returned by Component.Validate from any other errors, such as failed AWS
+// API calls, which prevented the Component from being fully validated.
+func splitFindings(err error) ([]Finding, error) {
+	if err == nil {
+		return nil, nil
+	}
+
+	var (
+		findings []Finding
+		errs     []error
+	)
+
+	for _, e := range unwrapErrors(err) {
+		var f Finding
+		if errors.As(e, &f) {
+			findings = append(findings, f)
+		} else {
+			errs = append(errs, e)
+		}
+	}
+
+	// Components may find problems in any order, e.g. when ranging over a map, so sort them to keep reports comparable
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].CheckId != findings[j].CheckId {
+			return findings[i].CheckId < findings[j].CheckId
+		}
+		if findings[i].ResourceId != findings[j].ResourceId {
+			return findings[i].ResourceId < findings[j].ResourceId
+		}
+		if findings[i].Expected != findings[j].Expected {
+			return findings[i].Expected < findings[j].Expected
+		}
+		return findings[i].Message < findings[j].Message
+	})
+
+	return findings, errors.Join(errs...)
+}
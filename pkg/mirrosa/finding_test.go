@@ -0,0 +1,49 @@
+package mirrosa
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplitFindings(t *testing.T) {
+	apiErr := errors.New("api error")
+	missingApi := Finding{CheckId: "MOCK-001", ResourceId: "Z1", Expected: "api A record", Message: "missing api"}
+	missingApps := Finding{CheckId: "MOCK-001", ResourceId: "Z1", Expected: "apps A record", Message: "missing apps"}
+	other := Finding{CheckId: "MOCK-002", ResourceId: "Z1"}
+
+	tests := []struct {
+		name        string
+		err         error
+		expected    []Finding
+		expectedErr bool
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name:     "sorted by check, resource and expected value",
+			err:      errors.Join(other, missingApps, missingApi),
+			expected: []Finding{missingApi, missingApps, other},
+		},
+		{
+			name:        "findings are kept next to an api error",
+			err:         errors.Join(missingApps, apiErr, missingApi),
+			expected:    []Finding{missingApi, missingApps},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			findings, err := splitFindings(test.err)
+			if !reflect.DeepEqual(findings, test.expected) {
+				t.Errorf("expected findings %+v, got %+v", test.expected, findings)
+			}
+
+			if (err != nil) != test.expectedErr {
+				t.Errorf("expected err %t, got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package mirrosa
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// componentGraph indexes the dependencies between a set of Components by their position in the set.
+// Dependencies on components outside the set are ignored, so that a subset of components can still be validated.
+type componentGraph struct {
+	components []Component
+
+	// index maps a Component's Name to its position in components
+	index map[string]int
+
+	// dependents lists the positions of the components that directly depend on each component
+	dependents [][]int
+
+	// dependencies counts how many components in the set each component directly depends on
+	dependencies []int
+}
+
+func newComponentGraph(components []Component) (*componentGraph, error) {
+	g := &componentGraph{
+		components:   components,
+		index:        make(map[string]int, len(components)),
+		dependents:   make([][]int, len(components)),
+		dependencies: make([]int, len(components)),
+	}
+
+	for i, component := range components {
+		if _, ok := g.index[component.Name()]; ok {
+			return nil, fmt.Errorf("component %s was specified more than once", component.Name())
+		}
+		g.index[component.Name()] = i
+	}
+
+	for i, component := range components {
+		for _, dep := range component.Dependencies() {
+			j, ok := g.index[dep]
+			if !ok {
+				continue
+			}
+			g.dependents[j] = append(g.dependents[j], i)
+			g.dependencies[i]++
+		}
+	}
+
+	return g, nil
+}
+
+// sortComponents returns components in topological order, so that each Component comes after all of its
+// dependencies. Otherwise, the given order is preserved.
+func sortComponents(components []Component) ([]Component, error) {
+	g, err := newComponentGraph(components)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		remaining = slices.Clone(g.dependencies)
+		ready     []int
+		sorted    = make([]Component, 0, len(components))
+	)
+
+	for i, n := range remaining {
+		if n == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	for len(ready) > 0 {
+		i := ready[0]
+		ready = ready[1:]
+		sorted = append(sorted, components[i])
+
+		for _, d := range g.dependents[i] {
+			remaining[d]--
+			if remaining[d] == 0 {
+				ready = append(ready, d)
+				slices.Sort(ready)
+			}
+		}
+	}
+
+	if len(sorted) != len(components) {
+		var cycle []string
+		for i, n := range remaining {
+			if n > 0 {
+				cycle = append(cycle, components[i].Name())
+			}
+		}
+		return nil, fmt.Errorf("dependency cycle between components: %s", strings.Join(cycle, ", "))
+	}
+
+	return sorted, nil
+}
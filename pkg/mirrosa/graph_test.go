@@ -0,0 +1,74 @@
+package mirrosa
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortComponents(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []Component
+		expected   []string
+		wantErr    bool
+	}{
+		{
+			name: "no dependencies preserves order",
+			components: []Component{
+				mockComponent{name: "b"},
+				mockComponent{name: "a"},
+			},
+			expected: []string{"b", "a"},
+		},
+		{
+			name: "dependencies first",
+			components: []Component{
+				mockComponent{name: "vpc", deps: []string{"hz"}},
+				mockComponent{name: "dhcp", deps: []string{"vpc"}},
+				mockComponent{name: "public"},
+				mockComponent{name: "hz"},
+			},
+			expected: []string{"public", "hz", "vpc", "dhcp"},
+		},
+		{
+			name: "unselected dependencies are ignored",
+			components: []Component{
+				mockComponent{name: "vpc", deps: []string{"hz"}},
+			},
+			expected: []string{"vpc"},
+		},
+		{
+			name: "cycle",
+			components: []Component{
+				mockComponent{name: "a", deps: []string{"b"}},
+				mockComponent{name: "b", deps: []string{"a"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate",
+			components: []Component{
+				mockComponent{name: "a"},
+				mockComponent{name: "a"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sorted, err := sortComponents(test.components)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("sortComponents() error = %v, wantErr %v", err, test.wantErr)
+			}
+
+			var names []string
+			for _, c := range sorted {
+				names = append(names, c.Name())
+			}
+			if !reflect.DeepEqual(names, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, names)
+			}
+		})
+	}
+}
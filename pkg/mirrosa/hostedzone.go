@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/route53"
@@ -27,6 +29,14 @@ const (
 	privateHostedZoneAppsRecordPrefix   = "\\052.apps"
 	privateHostedZoneApiRecordPrefix    = "api"
 	privateHostedZoneApiIntRecordPrefix = "api-int"
+
+	publicHostedZoneName  = "public-hosted-zone"
+	privateHostedZoneName = "private-hosted-zone"
+
+	publicHostedZoneExistsCheckId   = "MIRROSA-R53-001"
+	privateHostedZoneExistsCheckId  = "MIRROSA-R53-002"
+	privateHostedZoneRecordsCheckId = "MIRROSA-R53-003"
+	privateHostedZoneAliasesCheckId = "MIRROSA-R53-004"
 )
 
 // Ensure PublicHostedZone implements mirrosa.Component
@@ -48,10 +58,10 @@ type PublicHostedZone struct {
 
 func (c *Client) NewPublicHostedZone() PublicHostedZone {
 	return PublicHostedZone{
-		log:           c.log,
+		log:           c.log.With(slog.String("component", publicHostedZoneName)),
 		BaseDomain:    c.ClusterInfo.BaseDomain,
 		PrivateLink:   c.Cluster.AWS().PrivateLink(),
-		Route53Client: route53.NewFromConfig(c.AwsConfig),
+		Route53Client: c.awsClients().route53,
 	}
 }
 
@@ -69,11 +79,38 @@ func (p PublicHostedZone) Validate(ctx context.Context) error {
 	for _, hz := range hzs.HostedZones {
 		if !hz.Config.PrivateZone {
 			p.log.Info("found Public Hosted Zone", slog.String("id", *hz.Id))
+			recordResource(ctx, Resource{
+				Type: ResourceTypeHostedZone,
+				Id:   *hz.Id,
+				Name: aws.ToString(hz.Name),
+				Role: ResourceRolePublic,
+			})
 			return nil
 		}
 	}
 
-	return fmt.Errorf("no public hosted zone for %s found", expectedName)
+	return Finding{
+		CheckId:    publicHostedZoneExistsCheckId,
+		ResourceId: expectedName,
+		Expected:   "1 public hosted zone",
+		Actual:     "0 public hosted zones",
+		Severity:   SeverityError,
+		Message:    fmt.Sprintf("no public hosted zone for %s found", expectedName),
+	}
+}
+
+func (p PublicHostedZone) Name() string {
+	return publicHostedZoneName
+}
+
+func (p PublicHostedZone) Dependencies() []string {
+	return nil
+}
+
+func (p PublicHostedZone) Checks() []Check {
+	return []Check{
+		{Id: publicHostedZoneExistsCheckId, Title: "Public hosted zone exists"},
+	}
 }
 
 func (p PublicHostedZone) Description() string {
@@ -107,12 +144,12 @@ type PrivateHostedZone struct {
 
 func (c *Client) NewPrivateHostedZone() PrivateHostedZone {
 	return PrivateHostedZone{
-		log:           c.log,
+		log:           c.log.With(slog.String("component", privateHostedZoneName)),
 		ClusterName:   c.ClusterInfo.Name,
 		BaseDomain:    c.ClusterInfo.BaseDomain,
 		Region:        types.VPCRegion(c.Cluster.Region().ID()),
 		VpcId:         c.ClusterInfo.VpcId,
-		Route53Client: route53.NewFromConfig(c.AwsConfig),
+		Route53Client: c.awsClients().route53,
 	}
 }
 
@@ -150,6 +187,12 @@ func (p PrivateHostedZone) Validate(ctx context.Context) error {
 					if *vpc.VPCId == p.VpcId {
 						p.log.Info("found Private Hosted Zone", slog.String("id", *private.HostedZone.Id))
 						privateHostedZoneId = *private.HostedZone.Id
+						recordResource(ctx, Resource{
+							Type: ResourceTypeHostedZone,
+							Id:   privateHostedZoneId,
+							Name: aws.ToString(hz.Name),
+							Role: ResourceRolePrivate,
+						})
 						break
 					}
 				}
@@ -158,7 +201,14 @@ func (p PrivateHostedZone) Validate(ctx context.Context) error {
 	}
 
 	if privateHostedZoneId == "" {
-		return fmt.Errorf("no private hosted zone associated to %s for %s found", p.VpcId, expectedName)
+		return Finding{
+			CheckId:    privateHostedZoneExistsCheckId,
+			ResourceId: expectedName,
+			Expected:   fmt.Sprintf("private hosted zone associated with %s", p.VpcId),
+			Actual:     "no associated private hosted zone",
+			Severity:   SeverityError,
+			Message:    fmt.Sprintf("no private hosted zone associated to %s for %s found", p.VpcId, expectedName),
+		}
 	}
 
 	p.log.Info("validating records in Private Hosted Zone", slog.String("id", privateHostedZoneId))
@@ -176,6 +226,7 @@ func (p PrivateHostedZone) Validate(ctx context.Context) error {
 		fmt.Sprintf("%s.%s.%s.", privateHostedZoneAppsRecordPrefix, p.ClusterName, p.BaseDomain):   {},
 	}
 
+	var errs []error
 	for _, record := range records.ResourceRecordSets {
 		// If we've found all the required records, stop
 		if len(expectedRecords) == 0 {
@@ -186,19 +237,61 @@ func (p PrivateHostedZone) Validate(ctx context.Context) error {
 			p.log.Debug("found record", slog.String("name", *record.Name))
 			// All expected records are A records
 			if record.Type != types.RRTypeA || record.AliasTarget == nil {
-				return fmt.Errorf("%s has no value or an incorrect type", *record.Name)
+				actual := fmt.Sprintf("%s record", record.Type)
+				if record.AliasTarget == nil {
+					actual = fmt.Sprintf("%s record without an alias target", record.Type)
+				}
+				errs = append(errs, Finding{
+					CheckId:    privateHostedZoneAliasesCheckId,
+					ResourceId: privateHostedZoneId,
+					Expected:   fmt.Sprintf("%s A record with an alias target", *record.Name),
+					Actual:     actual,
+					Severity:   SeverityError,
+					Message:    fmt.Sprintf("%s has no value or an incorrect type", *record.Name),
+				})
 			}
 			delete(expectedRecords, *record.Name)
 		}
 	}
 
-	if len(expectedRecords) > 0 {
-		return fmt.Errorf("missing required records in private hosted zone %s", privateHostedZoneId)
+	for _, name := range slices.Sorted(maps.Keys(expectedRecords)) {
+		errs = append(errs, Finding{
+			CheckId:    privateHostedZoneRecordsCheckId,
+			ResourceId: privateHostedZoneId,
+			Expected:   fmt.Sprintf("%s A record", name),
+			Actual:     "no record",
+			Severity:   SeverityError,
+			Message:    fmt.Sprintf("missing required record %s in private hosted zone %s", name, privateHostedZoneId),
+		})
 	}
 
+	return errors.Join(errs...)
+}
+
+func (p PrivateHostedZone) Name() string {
+	return privateHostedZoneName
+}
+
+func (p PrivateHostedZone) Dependencies() []string {
 	return nil
 }
 
+func (p PrivateHostedZone) Checks() []Check {
+	return []Check{
+		{Id: privateHostedZoneExistsCheckId, Title: "Private hosted zone exists and is associated with the VPC", Prerequisite: true},
+		{
+			Id:       privateHostedZoneRecordsCheckId,
+			Title:    "Private hosted zone has api, api-int, and *.apps records",
+			Requires: []string{privateHostedZoneExistsCheckId},
+		},
+		{
+			Id:       privateHostedZoneAliasesCheckId,
+			Title:    "Private hosted zone records are A records with alias targets",
+			Requires: []string{privateHostedZoneExistsCheckId},
+		},
+	}
+}
+
 func (p PrivateHostedZone) Description() string {
 	return privateHostedZoneDescription
 }
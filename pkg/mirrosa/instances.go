@@ -2,8 +2,10 @@ package mirrosa
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -22,10 +24,24 @@ const instanceDescription = "EC2 instances directly correspond to Kubernetes nod
 	"2. https://kubernetes.io/docs/concepts/overview/components/\n" +
 	"3. https://docs.openshift.com/rosa/rosa_architecture/rosa_policy_service_definition/rosa-service-definition.html"
 
+const (
+	instancesName = "instances"
+
+	instancesControlPlaneCountCheckId   = "MIRROSA-EC2-001"
+	instancesControlPlaneRunningCheckId = "MIRROSA-EC2-002"
+	instancesInfraCountCheckId          = "MIRROSA-EC2-003"
+	instancesInfraRunningCheckId        = "MIRROSA-EC2-004"
+	instancesWorkerCountCheckId         = "MIRROSA-EC2-005"
+	instancesSecurityGroupCountCheckId  = "MIRROSA-EC2-006"
+	instancesWorkerRunningCheckId       = "MIRROSA-EC2-007"
+	instancesSecurityGroupNameCheckId   = "MIRROSA-EC2-008"
+)
+
 var _ Component = &Instances{}
 
 type MirrosaInstancesAPIClient interface {
 	ec2.DescribeInstancesAPIClient
+	ec2.DescribeSecurityGroupsAPIClient
 }
 
 type Instances struct {
@@ -38,10 +54,10 @@ type Instances struct {
 
 func (c *Client) NewInstances() Instances {
 	return Instances{
-		log:       c.log,
+		log:       c.log.With(slog.String("component", instancesName)),
 		InfraName: c.ClusterInfo.InfraName,
 		MultiAZ:   c.Cluster.MultiAZ(),
-		Ec2Client: ec2.NewFromConfig(c.AwsConfig),
+		Ec2Client: c.awsClients().ec2,
 	}
 }
 
@@ -64,6 +80,15 @@ func (i Instances) Validate(ctx context.Context) error {
 		}
 		for _, res := range out.Reservations {
 			instances = append(instances, res.Instances...)
+			for _, instance := range res.Instances {
+				name := ec2NameTag(instance.Tags)
+				recordResource(ctx, Resource{
+					Type: ResourceTypeInstance,
+					Id:   aws.ToString(instance.InstanceId),
+					Name: name,
+					Role: instanceRole(i.InfraName, name),
+				})
+			}
 		}
 		if out.NextToken == nil {
 			break
@@ -71,6 +96,23 @@ func (i Instances) Validate(ctx context.Context) error {
 		in.NextToken = out.NextToken
 	}
 
+	var errs []error
+
+	// The names of the security groups only feed a warning, so failing to look them up, e.g. because one of them
+	// was deleted, must not stop the other instance checks
+	groupNames, err := i.securityGroupNames(ctx, instances)
+	if err != nil {
+		i.log.Warn("could not look up the names of the instances' security groups", slog.String("error", err.Error()))
+		errs = append(errs, Finding{
+			CheckId:    instancesSecurityGroupNameCheckId,
+			ResourceId: i.InfraName,
+			Expected:   "security groups that can be described",
+			Actual:     err.Error(),
+			Severity:   SeverityWarning,
+			Message:    fmt.Sprintf("could not look up the names of the instances' security groups: %s", err),
+		})
+	}
+
 	// MASTER NODES VALIDATIONS
 	i.log.Info("validating cluster's control plane instances")
 	var masters []types.Instance
@@ -85,20 +127,36 @@ func (i Instances) Validate(ctx context.Context) error {
 
 	// Each cluster has 3 master nodes by default - immutable
 	if len(masters) != 3 {
-		return fmt.Errorf("there should be 3 control plane instances, found %d", len(masters))
+		errs = append(errs, Finding{
+			CheckId:    instancesControlPlaneCountCheckId,
+			ResourceId: i.InfraName,
+			Expected:   "3 control plane instances",
+			Actual:     fmt.Sprintf("%d control plane instances", len(masters)),
+			Severity:   SeverityError,
+			Message:    fmt.Sprintf("there should be 3 control plane instances, found %d", len(masters)),
+		})
 	}
 
 	// Check if masters are running
 	for _, v := range masters {
 		if v.State.Name != types.InstanceStateNameRunning {
-			return fmt.Errorf("found non running control plane instance: %s", *v.InstanceId)
+			errs = append(errs, Finding{
+				CheckId:    instancesControlPlaneRunningCheckId,
+				ResourceId: aws.ToString(v.InstanceId),
+				Expected:   string(types.InstanceStateNameRunning),
+				Actual:     string(v.State.Name),
+				Severity:   SeverityError,
+				Message:    fmt.Sprintf("found non running control plane instance: %s", aws.ToString(v.InstanceId)),
+			})
 		}
 
-		if len(v.SecurityGroups) != 1 {
-			return fmt.Errorf("one security group should be attached to %s: (%s-master-sg), got %d", *v.InstanceId, i.InfraName, len(v.SecurityGroups))
+		if err := i.validateSecurityGroupCount(v, fmt.Sprintf("%s-master-sg", i.InfraName)); err != nil {
+			errs = append(errs, err)
 		}
 
-		// TODO: Check if the security group is the correct one, with tag "Name: ${infra_name}-master-sg"
+		if err := i.validateSecurityGroupName(v, fmt.Sprintf("%s-master-sg", i.InfraName), groupNames); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
 	// INFRA NODES VALIDATIONS
@@ -114,24 +172,47 @@ func (i Instances) Validate(ctx context.Context) error {
 	}
 
 	if i.MultiAZ && len(infraNodes) < 3 {
-		return fmt.Errorf("there should be at least 3 infra instances for multi-AZ clusters")
+		errs = append(errs, Finding{
+			CheckId:    instancesInfraCountCheckId,
+			ResourceId: i.InfraName,
+			Expected:   "at least 3 infra instances",
+			Actual:     fmt.Sprintf("%d infra instances", len(infraNodes)),
+			Severity:   SeverityError,
+			Message:    "there should be at least 3 infra instances for multi-AZ clusters",
+		})
 	}
 
 	if !i.MultiAZ && len(infraNodes) < 2 {
-		return fmt.Errorf("there should be at least 2 infra instances for single-AZ clusters")
+		errs = append(errs, Finding{
+			CheckId:    instancesInfraCountCheckId,
+			ResourceId: i.InfraName,
+			Expected:   "at least 2 infra instances",
+			Actual:     fmt.Sprintf("%d infra instances", len(infraNodes)),
+			Severity:   SeverityError,
+			Message:    "there should be at least 2 infra instances for single-AZ clusters",
+		})
 	}
 
 	// Check if infras are running
 	for _, v := range infraNodes {
 		if v.State.Name != types.InstanceStateNameRunning {
-			return fmt.Errorf("found non running infra instances: %s", *v.InstanceId)
+			errs = append(errs, Finding{
+				CheckId:    instancesInfraRunningCheckId,
+				ResourceId: aws.ToString(v.InstanceId),
+				Expected:   string(types.InstanceStateNameRunning),
+				Actual:     string(v.State.Name),
+				Severity:   SeverityError,
+				Message:    fmt.Sprintf("found non running infra instances: %s", aws.ToString(v.InstanceId)),
+			})
 		}
 
-		if len(v.SecurityGroups) != 1 {
-			return fmt.Errorf("one security group should be attached to %s: (%s-worker-sg), got %d", *v.InstanceId, i.InfraName, len(v.SecurityGroups))
+		if err := i.validateSecurityGroupCount(v, fmt.Sprintf("%s-worker-sg", i.InfraName)); err != nil {
+			errs = append(errs, err)
 		}
 
-		// TODO: Check if the security group is the correct one, with tag "Name: ${infra_name}-worker-sg"
+		if err := i.validateSecurityGroupName(v, fmt.Sprintf("%s-worker-sg", i.InfraName), groupNames); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
 	// WORKER NODES VALIDATIONS
@@ -148,23 +229,65 @@ func (i Instances) Validate(ctx context.Context) error {
 
 	// Check if there are any worker nodes provisioned
 	if len(workerNodes) == 0 {
-		return fmt.Errorf("there should be at least 1 worker node running, otherwise CU workloads wouldn't be able to be schedulable")
+		errs = append(errs, Finding{
+			CheckId:    instancesWorkerCountCheckId,
+			ResourceId: i.InfraName,
+			Expected:   "at least 1 worker instance",
+			Actual:     "0 worker instances",
+			Severity:   SeverityError,
+			Message:    "there should be at least 1 worker node running, otherwise CU workloads wouldn't be able to be schedulable",
+		})
 	}
 
 	// Check if worker are running
 	for _, v := range workerNodes {
+		// Workers may be legitimately stopped or replaced by the machine-api, so this isn't fatal on its own
 		if v.State.Name != types.InstanceStateNameRunning {
-			i.log.Info("found non running worker nodes", slog.String("id", *v.InstanceId))
+			errs = append(errs, Finding{
+				CheckId:    instancesWorkerRunningCheckId,
+				ResourceId: aws.ToString(v.InstanceId),
+				Expected:   string(types.InstanceStateNameRunning),
+				Actual:     string(v.State.Name),
+				Severity:   SeverityWarning,
+				Message:    fmt.Sprintf("found non running worker instance: %s", aws.ToString(v.InstanceId)),
+			})
 		}
 
-		if len(v.SecurityGroups) != 1 {
-			return fmt.Errorf("one security group should be attached to %s: (%s-worker-sg), got %d", *v.InstanceId, i.InfraName, len(v.SecurityGroups))
+		if err := i.validateSecurityGroupCount(v, fmt.Sprintf("%s-worker-sg", i.InfraName)); err != nil {
+			errs = append(errs, err)
 		}
 
-		// TODO: Check if the security group is the correct one, with tag "Name: ${infra_name}-worker-sg"
+		if err := i.validateSecurityGroupName(v, fmt.Sprintf("%s-worker-sg", i.InfraName), groupNames); err != nil {
+			errs = append(errs, err)
+		}
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+func (i Instances) Name() string {
+	return instancesName
+}
+
+func (i Instances) Dependencies() []string {
+	return []string{vpcName}
+}
+
+func (i Instances) Checks() []Check {
+	return []Check{
+		{Id: instancesControlPlaneCountCheckId, Title: "3 control plane instances exist"},
+		{Id: instancesControlPlaneRunningCheckId, Title: "Control plane instances are running"},
+		{Id: instancesInfraCountCheckId, Title: "Enough infra instances exist for the cluster's availability zones"},
+		{Id: instancesInfraRunningCheckId, Title: "Infra instances are running"},
+		{Id: instancesWorkerCountCheckId, Title: "At least 1 worker instance exists"},
+		{Id: instancesSecurityGroupCountCheckId, Title: "Each instance has exactly 1 security group attached"},
+		{Id: instancesWorkerRunningCheckId, Title: "Worker instances are running", Severity: SeverityWarning},
+		{
+			Id:       instancesSecurityGroupNameCheckId,
+			Title:    "Each instance has its role's security group attached",
+			Severity: SeverityWarning,
+		},
+	}
 }
 
 func (i Instances) Description() string {
@@ -178,3 +301,80 @@ func (i Instances) FilterValue() string {
 func (i Instances) Title() string {
 	return "EC2 Instance"
 }
+
+// validateSecurityGroupCount returns a Finding if an instance does not have exactly one security group, expectedGroup, attached
+func (i Instances) validateSecurityGroupCount(instance types.Instance, expectedGroup string) error {
+	if len(instance.SecurityGroups) == 1 {
+		return nil
+	}
+
+	return Finding{
+		CheckId:    instancesSecurityGroupCountCheckId,
+		ResourceId: aws.ToString(instance.InstanceId),
+		Expected:   fmt.Sprintf("1 security group (%s)", expectedGroup),
+		Actual:     fmt.Sprintf("%d security groups", len(instance.SecurityGroups)),
+		Severity:   SeverityError,
+		Message:    fmt.Sprintf("one security group should be attached to %s: (%s), got %d", aws.ToString(instance.InstanceId), expectedGroup, len(instance.SecurityGroups)),
+	}
+}
+
+// securityGroupNames returns the Name tag of every security group attached to instances by security group id
+func (i Instances) securityGroupNames(ctx context.Context, instances []types.Instance) (map[string]string, error) {
+	var ids []string
+	for _, instance := range instances {
+		for _, group := range instance.SecurityGroups {
+			if group.GroupId != nil && !slices.Contains(ids, *group.GroupId) {
+				ids = append(ids, *group.GroupId)
+			}
+		}
+	}
+
+	names := map[string]string{}
+	if len(ids) == 0 {
+		return names, nil
+	}
+
+	resp, err := i.Ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+		GroupIds: ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range resp.SecurityGroups {
+		names[aws.ToString(group.GroupId)] = ec2NameTag(group.Tags)
+	}
+
+	return names, nil
+}
+
+// validateSecurityGroupName returns a warning Finding if none of the security groups attached to an instance has the
+// Name tag expectedGroup. Instances without any security group are already reported by validateSecurityGroupCount.
+func (i Instances) validateSecurityGroupName(instance types.Instance, expectedGroup string, groupNames map[string]string) error {
+	// groupNames is nil if the security groups could not be described, which is already reported
+	if len(instance.SecurityGroups) == 0 || groupNames == nil {
+		return nil
+	}
+
+	var actual []string
+	for _, group := range instance.SecurityGroups {
+		name := groupNames[aws.ToString(group.GroupId)]
+		if name == expectedGroup {
+			return nil
+		}
+		if name == "" {
+			name = aws.ToString(group.GroupId)
+		}
+		actual = append(actual, name)
+	}
+
+	// The instance may still work if the attached group allows the same traffic, so this isn't fatal on its own
+	return Finding{
+		CheckId:    instancesSecurityGroupNameCheckId,
+		ResourceId: aws.ToString(instance.InstanceId),
+		Expected:   expectedGroup,
+		Actual:     strings.Join(actual, ", "),
+		Severity:   SeverityWarning,
+		Message:    fmt.Sprintf("%s does not have the expected security group attached: (%s), got %s", aws.ToString(instance.InstanceId), expectedGroup, strings.Join(actual, ", ")),
+	}
+}
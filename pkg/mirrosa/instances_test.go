@@ -2,6 +2,7 @@ package mirrosa
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"os"
 	"testing"
@@ -12,18 +13,25 @@ import (
 )
 
 type mockMirrosaInstancesAPIClient struct {
-	describeInstancesResp *ec2.DescribeInstancesOutput
+	describeInstancesResp      *ec2.DescribeInstancesOutput
+	describeSecurityGroupsResp *ec2.DescribeSecurityGroupsOutput
+	describeSecurityGroupsErr  error
 }
 
 func (m mockMirrosaInstancesAPIClient) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(options *ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
 	return m.describeInstancesResp, nil
 }
 
+func (m mockMirrosaInstancesAPIClient) DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(options *ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error) {
+	return m.describeSecurityGroupsResp, m.describeSecurityGroupsErr
+}
+
 func TestInstances_Validate(t *testing.T) {
 	tests := []struct {
-		name      string
-		instances *Instances
-		expectErr bool
+		name          string
+		instances     *Instances
+		expectErr     bool
+		expectWarning bool
 	}{
 		{
 			name: "no instances",
@@ -44,6 +52,7 @@ func TestInstances_Validate(t *testing.T) {
 				InfraName: "mock",
 				MultiAZ:   true,
 				Ec2Client: &mockMirrosaInstancesAPIClient{
+					describeSecurityGroupsResp: mockInstanceSecurityGroups(),
 					describeInstancesResp: &ec2.DescribeInstancesOutput{
 						Reservations: []types.Reservation{
 							{
@@ -51,7 +60,7 @@ func TestInstances_Validate(t *testing.T) {
 								Instances: []types.Instance{
 									{
 										SecurityGroups: []types.GroupIdentifier{
-											{},
+											{GroupId: aws.String("sg-master")},
 										},
 										State: &types.InstanceState{Name: types.InstanceStateNameRunning},
 										Tags: []types.Tag{
@@ -60,7 +69,7 @@ func TestInstances_Validate(t *testing.T) {
 									},
 									{
 										SecurityGroups: []types.GroupIdentifier{
-											{},
+											{GroupId: aws.String("sg-master")},
 										},
 										State: &types.InstanceState{Name: types.InstanceStateNameRunning},
 										Tags: []types.Tag{
@@ -69,7 +78,7 @@ func TestInstances_Validate(t *testing.T) {
 									},
 									{
 										SecurityGroups: []types.GroupIdentifier{
-											{},
+											{GroupId: aws.String("sg-master")},
 										},
 										State: &types.InstanceState{Name: types.InstanceStateNameRunning},
 										Tags: []types.Tag{
@@ -78,7 +87,7 @@ func TestInstances_Validate(t *testing.T) {
 									},
 									{
 										SecurityGroups: []types.GroupIdentifier{
-											{},
+											{GroupId: aws.String("sg-worker")},
 										},
 										State: &types.InstanceState{Name: types.InstanceStateNameRunning},
 										Tags: []types.Tag{
@@ -87,7 +96,7 @@ func TestInstances_Validate(t *testing.T) {
 									},
 									{
 										SecurityGroups: []types.GroupIdentifier{
-											{},
+											{GroupId: aws.String("sg-worker")},
 										},
 										State: &types.InstanceState{Name: types.InstanceStateNameRunning},
 										Tags: []types.Tag{
@@ -96,7 +105,7 @@ func TestInstances_Validate(t *testing.T) {
 									},
 									{
 										SecurityGroups: []types.GroupIdentifier{
-											{},
+											{GroupId: aws.String("sg-worker")},
 										},
 										State: &types.InstanceState{Name: types.InstanceStateNameRunning},
 										Tags: []types.Tag{
@@ -105,7 +114,7 @@ func TestInstances_Validate(t *testing.T) {
 									},
 									{
 										SecurityGroups: []types.GroupIdentifier{
-											{},
+											{GroupId: aws.String("sg-worker")},
 										},
 										State: &types.InstanceState{Name: types.InstanceStateNameRunning},
 										Tags: []types.Tag{
@@ -114,7 +123,7 @@ func TestInstances_Validate(t *testing.T) {
 									},
 									{
 										SecurityGroups: []types.GroupIdentifier{
-											{},
+											{GroupId: aws.String("sg-worker")},
 										},
 										State: &types.InstanceState{Name: types.InstanceStateNameRunning},
 										Tags: []types.Tag{
@@ -129,6 +138,173 @@ func TestInstances_Validate(t *testing.T) {
 			},
 			expectErr: false,
 		},
+		{
+			name: "stopped worker",
+			instances: &Instances{
+				log:       slog.New(slog.NewTextHandler(os.Stdout, nil)),
+				InfraName: "mock",
+				MultiAZ:   false,
+				Ec2Client: &mockMirrosaInstancesAPIClient{
+					describeSecurityGroupsResp: mockInstanceSecurityGroups(),
+					describeInstancesResp: &ec2.DescribeInstancesOutput{
+						Reservations: []types.Reservation{
+							{
+								Instances: []types.Instance{
+									mockInstance("mock-master1", types.InstanceStateNameRunning, "sg-master"),
+									mockInstance("mock-master2", types.InstanceStateNameRunning, "sg-master"),
+									mockInstance("mock-master3", types.InstanceStateNameRunning, "sg-master"),
+									mockInstance("mock-infra1", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-infra2", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-worker1", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-worker2", types.InstanceStateNameStopped, "sg-worker"),
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr:     true,
+			expectWarning: true,
+		},
+		{
+			name: "worker with control plane security group",
+			instances: &Instances{
+				log:       slog.New(slog.NewTextHandler(os.Stdout, nil)),
+				InfraName: "mock",
+				MultiAZ:   false,
+				Ec2Client: &mockMirrosaInstancesAPIClient{
+					describeSecurityGroupsResp: mockInstanceSecurityGroups(),
+					describeInstancesResp: &ec2.DescribeInstancesOutput{
+						Reservations: []types.Reservation{
+							{
+								Instances: []types.Instance{
+									mockInstance("mock-master1", types.InstanceStateNameRunning, "sg-master"),
+									mockInstance("mock-master2", types.InstanceStateNameRunning, "sg-master"),
+									mockInstance("mock-master3", types.InstanceStateNameRunning, "sg-master"),
+									mockInstance("mock-infra1", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-infra2", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-worker1", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-worker2", types.InstanceStateNameRunning, "sg-master"),
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr:     true,
+			expectWarning: true,
+		},
+		{
+			name: "control plane with worker security group",
+			instances: &Instances{
+				log:       slog.New(slog.NewTextHandler(os.Stdout, nil)),
+				InfraName: "mock",
+				MultiAZ:   false,
+				Ec2Client: &mockMirrosaInstancesAPIClient{
+					describeSecurityGroupsResp: mockInstanceSecurityGroups(),
+					describeInstancesResp: &ec2.DescribeInstancesOutput{
+						Reservations: []types.Reservation{
+							{
+								Instances: []types.Instance{
+									mockInstance("mock-master1", types.InstanceStateNameRunning, "sg-master"),
+									mockInstance("mock-master2", types.InstanceStateNameRunning, "sg-master"),
+									mockInstance("mock-master3", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-infra1", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-infra2", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-worker1", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-worker2", types.InstanceStateNameRunning, "sg-worker"),
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr:     true,
+			expectWarning: true,
+		},
+		{
+			name: "worker with untagged security group",
+			instances: &Instances{
+				log:       slog.New(slog.NewTextHandler(os.Stdout, nil)),
+				InfraName: "mock",
+				MultiAZ:   false,
+				Ec2Client: &mockMirrosaInstancesAPIClient{
+					describeSecurityGroupsResp: mockInstanceSecurityGroups(),
+					describeInstancesResp: &ec2.DescribeInstancesOutput{
+						Reservations: []types.Reservation{
+							{
+								Instances: []types.Instance{
+									mockInstance("mock-master1", types.InstanceStateNameRunning, "sg-master"),
+									mockInstance("mock-master2", types.InstanceStateNameRunning, "sg-master"),
+									mockInstance("mock-master3", types.InstanceStateNameRunning, "sg-master"),
+									mockInstance("mock-infra1", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-infra2", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-worker1", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-worker2", types.InstanceStateNameRunning, "sg-untagged"),
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr:     true,
+			expectWarning: true,
+		},
+		{
+			name: "security groups cannot be described",
+			instances: &Instances{
+				log:       slog.New(slog.NewTextHandler(os.Stdout, nil)),
+				InfraName: "mock",
+				MultiAZ:   false,
+				Ec2Client: &mockMirrosaInstancesAPIClient{
+					describeSecurityGroupsErr: errors.New("InvalidGroup.NotFound"),
+					describeInstancesResp: &ec2.DescribeInstancesOutput{
+						Reservations: []types.Reservation{
+							{
+								Instances: []types.Instance{
+									mockInstance("mock-master1", types.InstanceStateNameRunning, "sg-master"),
+									mockInstance("mock-master2", types.InstanceStateNameRunning, "sg-master"),
+									mockInstance("mock-master3", types.InstanceStateNameRunning, "sg-master"),
+									mockInstance("mock-infra1", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-infra2", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-worker1", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-worker2", types.InstanceStateNameRunning, "sg-deleted"),
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr:     true,
+			expectWarning: true,
+		},
+		{
+			name: "other checks run when security groups cannot be described",
+			instances: &Instances{
+				log:       slog.New(slog.NewTextHandler(os.Stdout, nil)),
+				InfraName: "mock",
+				MultiAZ:   false,
+				Ec2Client: &mockMirrosaInstancesAPIClient{
+					describeSecurityGroupsErr: errors.New("UnauthorizedOperation"),
+					describeInstancesResp: &ec2.DescribeInstancesOutput{
+						Reservations: []types.Reservation{
+							{
+								Instances: []types.Instance{
+									mockInstance("mock-master1", types.InstanceStateNameRunning, "sg-master"),
+									mockInstance("mock-master2", types.InstanceStateNameRunning, "sg-master"),
+									mockInstance("mock-master3", types.InstanceStateNameStopped, "sg-master"),
+									mockInstance("mock-infra1", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-infra2", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-worker1", types.InstanceStateNameRunning, "sg-worker"),
+									mockInstance("mock-worker2", types.InstanceStateNameRunning, "sg-worker"),
+								},
+							},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -138,6 +314,15 @@ func TestInstances_Validate(t *testing.T) {
 				if !test.expectErr {
 					t.Errorf("expected no err, got %v", err)
 				}
+
+				findings, apiErr := splitFindings(err)
+				if apiErr != nil {
+					t.Errorf("expected only findings, got %v", apiErr)
+				}
+
+				if warning := findingsStatus(findings) == StatusWarning; warning != test.expectWarning {
+					t.Errorf("expected only warnings: %t, got %v", test.expectWarning, err)
+				}
 			} else {
 				if test.expectErr {
 					t.Error("expected err, got nil")
@@ -146,3 +331,35 @@ func TestInstances_Validate(t *testing.T) {
 		})
 	}
 }
+
+// mockInstance returns an instance in state with the given Name tag and a single security group, groupId
+func mockInstance(name string, state types.InstanceStateName, groupId string) types.Instance {
+	return types.Instance{
+		InstanceId:     aws.String(name),
+		SecurityGroups: []types.GroupIdentifier{{GroupId: aws.String(groupId)}},
+		State:          &types.InstanceState{Name: state},
+		Tags: []types.Tag{
+			{Key: aws.String("Name"), Value: aws.String(name)},
+		},
+	}
+}
+
+// mockInstanceSecurityGroups returns the control plane and worker security groups of the "mock" cluster, as well as
+// one without a Name tag
+func mockInstanceSecurityGroups() *ec2.DescribeSecurityGroupsOutput {
+	return &ec2.DescribeSecurityGroupsOutput{
+		SecurityGroups: []types.SecurityGroup{
+			{
+				GroupId: aws.String("sg-master"),
+				Tags:    []types.Tag{{Key: aws.String("Name"), Value: aws.String("mock-master-sg")}},
+			},
+			{
+				GroupId: aws.String("sg-worker"),
+				Tags:    []types.Tag{{Key: aws.String("Name"), Value: aws.String("mock-worker-sg")}},
+			},
+			{
+				GroupId: aws.String("sg-untagged"),
+			},
+		},
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
 	"github.com/mjlshen/mirrosa/pkg/ocm"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 )
@@ -25,21 +28,69 @@ type Client struct {
 
 	// ClusterInfo contains information about the ROSA cluster that will be used to validate it
 	ClusterInfo *ClusterInfo
+
+	// Parallelism is the maximum number of components validated concurrently, values less than 1 are treated as 1
+	Parallelism int
+
+	// ComponentTimeout bounds how long validating a single component may take, zero means no timeout
+	ComponentTimeout time.Duration
+
+	// IgnoreBlockers validates every Component even if one of its dependencies failed a prerequisite check, e.g. to
+	// discover every resource of a cluster regardless of how broken it is
+	IgnoreBlockers bool
+
+	// aws holds the AWS API clients shared by every component, see awsClients
+	aws *awsClients
+
+	// vpc and subnets are the Resources found by FindVpcId, which are added to the Report by ValidateComponents
+	vpc     Resource
+	subnets []Resource
+
+	// dhcpOptionsId is the id of the DHCP Options Set attached to the VPC, as found by FindVpcId
+	dhcpOptionsId string
+
+	// opts holds the Options the Client was created with
+	opts Options
+}
+
+// Options customizes how a Client is created
+type Options struct {
+	// AwsObserver, if set, is called with every AWS API call made while discovering and validating the cluster
+	AwsObserver AwsObserver
+
+	// OcmObserver, if set, is called with the cluster object that OCM returned for the cluster id
+	OcmObserver OcmObserver
+
+	// AwsEndpoints, if set, override the endpoints of the AWS APIs used to validate the cluster
+	AwsEndpoints AwsEndpoints
+
+	// LoadAwsConfig, if set, returns the AWS configuration used to validate a cluster looked up in OCM instead of
+	// getting credentials from backplane
+	LoadAwsConfig func(cluster *cmv1.Cluster) (aws.Config, error)
 }
 
 // ClusterInfo contains information about the ROSA cluster that will be used to validate it
 type ClusterInfo struct {
 	// Name of the cluster
-	Name string
+	Name string `json:"name"`
 
 	// InfraName is the name with an additional slug that hive gives a ROSA cluster
-	InfraName string
+	InfraName string `json:"infraName"`
 
 	// BaseDomain is the DNS base domain of the cluster
-	BaseDomain string
+	BaseDomain string `json:"baseDomain"`
 
 	// VpcId is the AWS ID of the VPC the cluster is installed in
-	VpcId string
+	VpcId string `json:"vpcId"`
+
+	// SubnetIds are the AWS IDs of the subnets in the cluster's VPC
+	SubnetIds []string `json:"subnetIds,omitempty"`
+
+	// AccountId is the ID of the AWS account the cluster is installed in
+	AccountId string `json:"accountId"`
+
+	// Region is the AWS region the cluster is installed in
+	Region string `json:"region"`
 }
 
 func (c ClusterInfo) LogValue() slog.Value {
@@ -48,12 +99,19 @@ func (c ClusterInfo) LogValue() slog.Value {
 		slog.String("infraName", c.InfraName),
 		slog.String("baseDomain", c.BaseDomain),
 		slog.String("vpcId", c.VpcId),
+		slog.Any("subnetIds", c.SubnetIds),
+		slog.String("accountId", c.AccountId),
+		slog.String("region", c.Region),
 	)
 }
 
+// OcmObserver is called with the cluster object that OCM returned when looking up clusterId, which may be a name,
+// internal id, or external id
+type OcmObserver func(clusterId string, cluster *cmv1.Cluster)
+
 // NewClient looks up information in OCM about a given cluster id and returns a new
 // mirrosa client. Requires valid AWS and OCM credentials to be present beforehand.
-func NewClient(logger *slog.Logger, clusterId string) (*Client, error) {
+func NewClient(logger *slog.Logger, clusterId string, opts Options) (*Client, error) {
 	ocmConn, err := ocm.CreateConnection()
 	if err != nil {
 		return nil, err
@@ -68,54 +126,101 @@ func NewClient(logger *slog.Logger, clusterId string) (*Client, error) {
 		return nil, err
 	}
 
+	if opts.OcmObserver != nil {
+		opts.OcmObserver(clusterId, cluster)
+	}
+
 	if cluster.CloudProvider().ID() != "aws" {
 		return nil, fmt.Errorf("incompatible cloud provider: %s, mirrosa is only compatible with ROSA (AWS) clusters", cluster.CloudProvider().ID())
 	}
 
+	if opts.LoadAwsConfig != nil {
+		cfg, err := opts.LoadAwsConfig(cluster)
+		if err != nil {
+			return nil, err
+		}
+
+		return newClient(logger, cluster, cfg, opts), nil
+	}
+
 	cfg, err := ocm.GetCloudCredentials(ocmConn, cluster)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate cloud credentials: %w", err)
 	}
 
-	c := &Client{
+	return newClient(logger, cluster, cfg, opts), nil
+}
+
+// newClient returns a new mirrosa client for a cluster object from OCM that calls AWS with cfg
+func newClient(logger *slog.Logger, cluster *cmv1.Cluster, cfg aws.Config, opts Options) *Client {
+	return &Client{
 		AwsConfig: cfg,
 		Cluster:   cluster,
 		ClusterInfo: &ClusterInfo{
-			Name: cluster.Name(),
+			Name:      cluster.Name(),
+			AccountId: cluster.AWS().AccountID(),
+			Region:    cluster.Region().ID(),
 		},
-		log: logger,
+		log:  logger,
+		opts: opts,
+	}
+}
+
+func NewRosaClient(ctx context.Context, logger *slog.Logger, clusterId string, opts Options) (*Client, error) {
+	c, err := NewClient(logger, clusterId, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.discoverRosa(ctx); err != nil {
+		return nil, err
 	}
 
 	return c, nil
 }
 
-func NewRosaClient(ctx context.Context, logger *slog.Logger, clusterId string) (*Client, error) {
-	c, err := NewClient(logger, clusterId)
-	if err != nil {
+// NewRosaClientFromCluster is like NewRosaClient for a cluster object that was already looked up in OCM or read from a
+// file and calls AWS with cfg, e.g. to validate a cluster or replay a recorded one without OCM or backplane.
+func NewRosaClientFromCluster(ctx context.Context, logger *slog.Logger, cluster *cmv1.Cluster, cfg aws.Config, opts Options) (*Client, error) {
+	if opts.OcmObserver != nil {
+		opts.OcmObserver(cluster.ID(), cluster)
+	}
+
+	if cluster.CloudProvider().ID() != "aws" {
+		return nil, fmt.Errorf("incompatible cloud provider: %s, mirrosa is only compatible with ROSA (AWS) clusters", cluster.CloudProvider().ID())
+	}
+
+	c := newClient(logger, cluster, cfg, opts)
+	if err := c.discoverRosa(ctx); err != nil {
 		return nil, err
 	}
 
+	return c, nil
+}
+
+// discoverRosa checks that c.Cluster is a supported ROSA cluster and discovers the rest of c.ClusterInfo from it
+func (c *Client) discoverRosa(ctx context.Context) error {
 	if c.Cluster.Product().ID() != "rosa" && c.Cluster.Product().ID() != "osd" {
-		return nil, fmt.Errorf("incompatible product type: %s, mirrosa is only compatible with ROSA clusters", c.Cluster.Product().ID())
+		return fmt.Errorf("incompatible product type: %s, mirrosa is only compatible with ROSA clusters", c.Cluster.Product().ID())
 	}
 
 	if !c.Cluster.CCS().Enabled() {
-		return nil, errors.New("mirrosa is only compatible with CCS clusters")
+		return errors.New("mirrosa is only compatible with CCS clusters")
 	}
 
 	c.ClusterInfo.InfraName = c.Cluster.InfraID()
 	c.ClusterInfo.BaseDomain = c.Cluster.DNS().BaseDomain()
 
 	if err := c.FindVpcId(ctx); err != nil {
-		return nil, fmt.Errorf("failed to find vpc id: %w", err)
+		return fmt.Errorf("failed to find vpc id: %w", err)
 	}
 
-	return c, nil
+	return nil
 }
 
 // FindVpcId determines c.ClusterInfo.VpcId by determining the AWS VPC ID of a cluster
 func (c *Client) FindVpcId(ctx context.Context) error {
-	ec2Client := ec2.NewFromConfig(c.AwsConfig)
+	ec2Client := c.awsClients().ec2
 
 	if len(c.Cluster.AWS().SubnetIDs()) == 0 {
 		// Non-BYOVPC, use the cluster's infra name to find the VPC id of the cluster
@@ -140,10 +245,27 @@ func (c *Client) FindVpcId(ctx context.Context) error {
 			return fmt.Errorf("no VPCs found with expected Name tag: %s-vpc", c.ClusterInfo.InfraName)
 		case 1:
 			c.ClusterInfo.VpcId = *resp.Vpcs[0].VpcId
-			return nil
+			c.vpc = Resource{Type: ResourceTypeVpc, Id: c.ClusterInfo.VpcId, Name: ec2NameTag(resp.Vpcs[0].Tags)}
+			c.dhcpOptionsId = aws.ToString(resp.Vpcs[0].DhcpOptionsId)
 		default:
 			return fmt.Errorf("multiple VPCs found with the expected Name tag: %s-vpc", c.ClusterInfo.InfraName)
 		}
+
+		subnets, err := ec2Client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+			Filters: []types.Filter{
+				{
+					Name:   aws.String("vpc-id"),
+					Values: []string{c.ClusterInfo.VpcId},
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to describe subnets of vpc %s: %w", c.ClusterInfo.VpcId, err)
+		}
+
+		c.addSubnets(subnets.Subnets)
+
+		return nil
 	} else {
 		// BYOVPC, use the provided subnets to find the VPC id of the cluster
 		resp, err := ec2Client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{SubnetIds: c.Cluster.AWS().SubnetIDs()})
@@ -156,17 +278,237 @@ func (c *Client) FindVpcId(ctx context.Context) error {
 		}
 
 		c.ClusterInfo.VpcId = *resp.Subnets[0].VpcId
+		vpcs, err := ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{VpcIds: []string{c.ClusterInfo.VpcId}})
+		if err != nil {
+			return fmt.Errorf("failed to describe vpc %s: %w", c.ClusterInfo.VpcId, err)
+		}
+
+		if len(vpcs.Vpcs) != 1 {
+			return fmt.Errorf("unexpectedly received %d VPCs when describing: %s", len(vpcs.Vpcs), c.ClusterInfo.VpcId)
+		}
+
+		c.vpc = Resource{Type: ResourceTypeVpc, Id: c.ClusterInfo.VpcId, Name: ec2NameTag(vpcs.Vpcs[0].Tags)}
+		c.dhcpOptionsId = aws.ToString(vpcs.Vpcs[0].DhcpOptionsId)
+		c.addSubnets(resp.Subnets)
+
 		return nil
 	}
 }
 
-// ValidateComponents wraps the Validate method on one or many Component(s)
-func (c *Client) ValidateComponents(ctx context.Context, components ...Component) error {
-	for _, component := range components {
-		if err := component.Validate(ctx); err != nil {
-			return fmt.Errorf("%s: %w", component.Description(), err)
+// addSubnets records the subnets of the cluster's VPC
+func (c *Client) addSubnets(subnets []types.Subnet) {
+	for _, subnet := range subnets {
+		c.ClusterInfo.SubnetIds = append(c.ClusterInfo.SubnetIds, aws.ToString(subnet.SubnetId))
+		c.subnets = append(c.subnets, Resource{
+			Type: ResourceTypeSubnet,
+			Id:   aws.ToString(subnet.SubnetId),
+			Name: ec2NameTag(subnet.Tags),
+			Role: subnetRole(subnet.Tags),
+		})
+	}
+}
+
+// ValidateComponents validates every Component, continuing past any that fail, and returns a Report of the results.
+// Components are validated in topological order of their dependencies, up to c.Parallelism at a time. A Component is
+// only validated once all of its dependencies have passed their prerequisite checks, otherwise it is skipped unless
+// c.IgnoreBlockers is set.
+//
+// If ctx is cancelled or times out, Components that are being validated are reported as cancelled or timed out and
+// Components that have not started yet are not run, so the Report is still complete but partial.
+func (c *Client) ValidateComponents(ctx context.Context, components ...Component) (Report, error) {
+	started := time.Now()
+	sorted, err := sortComponents(components)
+	if err != nil {
+		return Report{}, err
+	}
+
+	g, err := newComponentGraph(sorted)
+	if err != nil {
+		return Report{}, err
+	}
+
+	rc := &resourceCollector{}
+	ctx = withResourceCollector(ctx, rc)
+
+	// The VPC and its subnets are found while building the Client rather than by a Component, so they are attributed
+	// to the Vpc but listed even if it is skipped or not selected
+	discovered := withResourceComponent(ctx, vpcName)
+	recordResource(discovered, c.vpc)
+	for _, subnet := range c.subnets {
+		recordResource(discovered, subnet)
+	}
+
+	var (
+		results   = make([]Result, len(sorted))
+		remaining = slices.Clone(g.dependencies)
+		done      = make(chan int)
+		ready     []int
+		running   int
+		finished  int
+	)
+
+	for i, n := range remaining {
+		if n == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	// release marks the i-th component as finished, readying any dependents that were only waiting on it
+	release := func(i int) {
+		finished++
+		for _, d := range g.dependents[i] {
+			remaining[d]--
+			if remaining[d] == 0 {
+				ready = append(ready, d)
+				slices.Sort(ready)
+			}
+		}
+	}
+
+	for finished < len(sorted) {
+		for len(ready) > 0 && running < max(c.Parallelism, 1) {
+			i := ready[0]
+			ready = ready[1:]
+
+			if ctx.Err() != nil {
+				reason := fmt.Sprintf("run ended before starting: %s", ctx.Err())
+				results[i] = Result{
+					Component: sorted[i],
+					Status:    StatusNotRun,
+					Reason:    reason,
+					Checks:    skipChecks(sorted[i].Checks(), reason),
+				}
+				release(i)
+				continue
+			}
+
+			if blocker := c.blockedBy(g, results, sorted[i]); blocker != nil {
+				c.log.Info("skipping component", slog.String("component", sorted[i].Name()), slog.String("blockedBy", blocker.Name()))
+				reason := fmt.Sprintf("blocked by %s", blocker.Title())
+				results[i] = Result{
+					Component: sorted[i],
+					Status:    StatusSkipped,
+					Reason:    reason,
+					Checks:    skipChecks(sorted[i].Checks(), reason),
+				}
+				release(i)
+				continue
+			}
+
+			running++
+			go func() {
+				results[i] = c.validateComponent(ctx, sorted[i])
+				done <- i
+			}()
+		}
+
+		if finished == len(sorted) {
+			break
+		}
+
+		i := <-done
+		running--
+		release(i)
+	}
+
+	report := Report{
+		Started:   started,
+		Duration:  time.Since(started),
+		Results:   results,
+		Resources: rc.list(),
+	}
+	if c.ClusterInfo != nil {
+		report.Cluster = *c.ClusterInfo
+	}
+	if c.aws != nil {
+		report.ApiCalls = c.aws.recorder.snapshot()
+	}
+
+	return report, nil
+}
+
+// blockedBy returns the first dependency of component that blocks its dependents, or nil if there are none or
+// c.IgnoreBlockers is set
+func (c *Client) blockedBy(g *componentGraph, results []Result, component Component) Component {
+	if c.IgnoreBlockers {
+		return nil
+	}
+
+	for _, dep := range component.Dependencies() {
+		j, ok := g.index[dep]
+		if !ok {
+			continue
+		}
+
+		if results[j].blocksDependents() {
+			return results[j].Component
 		}
 	}
 
 	return nil
 }
+
+// validateComponent validates a single Component, bounded by c.ComponentTimeout, and records its outcome as a Result
+func (c *Client) validateComponent(ctx context.Context, component Component) Result {
+	log := c.log.With(slog.String("component", component.Name()))
+	log.Debug("validating component")
+
+	componentCtx := withResourceComponent(ctx, component.Name())
+	if c.ComponentTimeout > 0 {
+		var cancel context.CancelFunc
+		componentCtx, cancel = context.WithTimeout(componentCtx, c.ComponentTimeout)
+		defer cancel()
+	}
+
+	result := Result{Component: component, Status: StatusPassed}
+	if err := component.Validate(componentCtx); err != nil {
+		result.Findings, result.Err = splitFindings(err)
+		switch {
+		case result.Err == nil:
+			result.Status = findingsStatus(result.Findings)
+		case findingsStatus(result.Findings) == StatusFailed:
+			// The cluster is known to be misconfigured, even though validation did not finish
+			result.Status = StatusFailed
+		default:
+			result.Status = StatusError
+			result.Reason = errorReason(result.Err)
+		}
+
+		// Validation that gave up because its context ended didn't fail, it didn't finish
+		if result.Err != nil && componentCtx.Err() != nil {
+			switch {
+			case errors.Is(ctx.Err(), context.Canceled):
+				result.Status = StatusCancelled
+				result.Reason = "run was cancelled"
+			case ctx.Err() != nil:
+				result.Status = StatusTimedOut
+				result.Reason = "run timed out"
+			default:
+				result.Status = StatusTimedOut
+				result.Reason = fmt.Sprintf("exceeded component timeout of %s", c.ComponentTimeout)
+			}
+		}
+
+		switch result.Status {
+		case StatusWarning:
+			log.Warn("component passed validation with warnings", slog.String("warnings", err.Error()))
+		case StatusError:
+			log.Error("component could not be validated", slog.String("error", err.Error()))
+		default:
+			log.Error("component failed validation", slog.String("error", err.Error()))
+		}
+	}
+	result.Checks = checkResults(component.Checks(), result.Findings, result.Err)
+
+	return result
+}
+
+// errorReason explains an error that prevented a Component from being validated, distinguishing AWS API errors
+func errorReason(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return fmt.Sprintf("AWS API error: %s", apiErr.ErrorCode())
+	}
+
+	return "could not finish validating"
+}
@@ -0,0 +1,458 @@
+package mirrosa
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+)
+
+type mockComponent struct {
+	name     string
+	deps     []string
+	checks   []Check
+	validate func(ctx context.Context) error
+}
+
+func (m mockComponent) Name() string           { return m.name }
+func (m mockComponent) Dependencies() []string { return m.deps }
+func (m mockComponent) FilterValue() string    { return m.name }
+func (m mockComponent) Title() string          { return m.name }
+func (m mockComponent) Description() string    { return m.name }
+func (m mockComponent) Checks() []Check        { return m.checks }
+
+func (m mockComponent) Validate(ctx context.Context) error {
+	if m.validate == nil {
+		return nil
+	}
+	return m.validate(ctx)
+}
+
+// returns creates a validate func for a mockComponent that always returns err
+func returns(err error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return err
+	}
+}
+
+func TestClient_ValidateComponents(t *testing.T) {
+	var ranLast bool
+	c := &Client{log: slog.New(slog.NewTextHandler(os.Stdout, nil))}
+
+	report, err := c.ValidateComponents(context.TODO(),
+		mockComponent{name: "first", validate: returns(Finding{CheckId: "MOCK-001"})},
+		mockComponent{name: "second"},
+		mockComponent{
+			name: "third",
+			validate: func(ctx context.Context) error {
+				ranLast = true
+				return errors.Join(Finding{CheckId: "MOCK-003"}, errors.New("api error"), Finding{CheckId: "MOCK-002"})
+			},
+		},
+	)
+
+	if err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	if !ranLast {
+		t.Error("expected every component to be validated after a failure")
+	}
+
+	if !report.HasFindings(SeverityError) {
+		t.Error("expected report to have findings")
+	}
+
+	tests := []struct {
+		name     string
+		checkIds []string
+		wantErr  bool
+	}{
+		{name: "first", checkIds: []string{"MOCK-001"}},
+		{name: "second", checkIds: nil},
+		{name: "third", checkIds: []string{"MOCK-002", "MOCK-003"}, wantErr: true},
+	}
+
+	if len(report.Results) != len(tests) {
+		t.Fatalf("expected %d results, got %d", len(tests), len(report.Results))
+	}
+
+	for i, test := range tests {
+		result := report.Results[i]
+		if result.Component.Name() != test.name {
+			t.Errorf("expected result %d to be %s, got %s", i, test.name, result.Component.Name())
+		}
+
+		if (result.Err != nil) != test.wantErr {
+			t.Errorf("%s: Err = %v, wantErr %v", test.name, result.Err, test.wantErr)
+		}
+
+		var checkIds []string
+		for _, f := range result.Findings {
+			checkIds = append(checkIds, f.CheckId)
+		}
+		if !reflect.DeepEqual(checkIds, test.checkIds) {
+			t.Errorf("%s: expected findings %v, got %v", test.name, test.checkIds, checkIds)
+		}
+	}
+}
+
+func TestClient_ValidateComponents_Parallelism(t *testing.T) {
+	tests := []struct {
+		name        string
+		parallelism int
+		expectedMax int
+	}{
+		{
+			name:        "unset is sequential",
+			parallelism: 0,
+			expectedMax: 1,
+		},
+		{
+			name:        "bounded",
+			parallelism: 2,
+			expectedMax: 2,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var (
+				mu                    sync.Mutex
+				inFlight, maxInFlight int
+			)
+			validate := func(ctx context.Context) error {
+				mu.Lock()
+				inFlight++
+				maxInFlight = max(maxInFlight, inFlight)
+				mu.Unlock()
+
+				time.Sleep(10 * time.Millisecond)
+
+				mu.Lock()
+				inFlight--
+				mu.Unlock()
+				return nil
+			}
+
+			c := &Client{
+				log:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+				Parallelism: test.parallelism,
+			}
+
+			var components []Component
+			for _, name := range []string{"a", "b", "c", "d", "e"} {
+				components = append(components, mockComponent{name: name, validate: validate})
+			}
+
+			report, err := c.ValidateComponents(context.TODO(), components...)
+			if err != nil {
+				t.Fatalf("expected no err, got %v", err)
+			}
+
+			if maxInFlight != test.expectedMax {
+				t.Errorf("expected at most %d components validated concurrently, got %d", test.expectedMax, maxInFlight)
+			}
+
+			for i, result := range report.Results {
+				if result.Component.Name() != components[i].Name() {
+					t.Errorf("expected result %d to be %s, got %s", i, components[i].Name(), result.Component.Name())
+				}
+			}
+		})
+	}
+}
+
+func TestClient_ValidateComponents_Dependencies(t *testing.T) {
+	var ranBlocked bool
+	c := &Client{
+		log:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		Parallelism: 4,
+	}
+
+	report, err := c.ValidateComponents(context.TODO(),
+		mockComponent{
+			name:   "blocked",
+			deps:   []string{"broken"},
+			checks: []Check{{Id: "MOCK-002"}},
+			validate: func(ctx context.Context) error {
+				ranBlocked = true
+				return nil
+			},
+		},
+		mockComponent{name: "transitive", deps: []string{"blocked"}},
+		mockComponent{name: "healthy", deps: []string{"root", "not-selected"}},
+		mockComponent{name: "broken", deps: []string{"root"}, validate: returns(Finding{CheckId: "MOCK-001"})},
+		mockComponent{name: "root", validate: returns(Finding{CheckId: "MOCK-000", Severity: SeverityWarning})},
+	)
+	if err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	if ranBlocked {
+		t.Error("expected component with a failed dependency to not be validated")
+	}
+
+	expected := []struct {
+		name   string
+		status Status
+	}{
+		{name: "root", status: StatusWarning},
+		{name: "healthy", status: StatusPassed},
+		{name: "broken", status: StatusFailed},
+		{name: "blocked", status: StatusSkipped},
+		{name: "transitive", status: StatusSkipped},
+	}
+
+	if len(report.Results) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(report.Results))
+	}
+
+	for i, e := range expected {
+		result := report.Results[i]
+		if result.Component.Name() != e.name || result.Status != e.status {
+			t.Errorf("expected result %d to be %s %s, got %s %s", i, e.name, e.status, result.Component.Name(), result.Status)
+		}
+	}
+
+	if checks := report.Results[3].Checks; len(checks) != 1 || checks[0].Status != StatusSkipped {
+		t.Errorf("expected the checks of a skipped component to be skipped, got %+v", checks)
+	}
+}
+
+func TestClient_ValidateComponents_Prerequisites(t *testing.T) {
+	checks := []Check{
+		{Id: "MOCK-001", Prerequisite: true},
+		{Id: "MOCK-002", Requires: []string{"MOCK-001"}},
+	}
+
+	tests := []struct {
+		name           string
+		err            error
+		ignoreBlockers bool
+		expectBlocked  bool
+	}{
+		{
+			name: "passed",
+		},
+		{
+			name: "failed check that is not a prerequisite",
+			err:  Finding{CheckId: "MOCK-002"},
+		},
+		{
+			name:          "failed prerequisite",
+			err:           Finding{CheckId: "MOCK-001"},
+			expectBlocked: true,
+		},
+		{
+			name:          "prerequisite could not be evaluated",
+			err:           errors.New("api error"),
+			expectBlocked: true,
+		},
+		{
+			name: "prerequisite with a warning",
+			err:  Finding{CheckId: "MOCK-001", Severity: SeverityWarning},
+		},
+		{
+			name:           "failed prerequisite with blockers ignored",
+			err:            Finding{CheckId: "MOCK-001"},
+			ignoreBlockers: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &Client{
+				log:            slog.New(slog.NewTextHandler(os.Stdout, nil)),
+				IgnoreBlockers: test.ignoreBlockers,
+			}
+			report, err := c.ValidateComponents(context.TODO(),
+				mockComponent{name: "dependency", checks: checks, validate: returns(test.err)},
+				mockComponent{name: "dependent", deps: []string{"dependency"}},
+			)
+			if err != nil {
+				t.Fatalf("expected no err, got %v", err)
+			}
+
+			if blocked := report.Results[1].Status == StatusSkipped; blocked != test.expectBlocked {
+				t.Errorf("expected blocked %t, got %s", test.expectBlocked, report.Results[1].Status)
+			}
+		})
+	}
+}
+
+func TestClient_ValidateComponents_Timeout(t *testing.T) {
+	// hangs blocks until its context ends, like a hung AWS API call
+	hangs := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	t.Run("component timeout", func(t *testing.T) {
+		c := &Client{
+			log:              slog.New(slog.NewTextHandler(os.Stdout, nil)),
+			ComponentTimeout: 10 * time.Millisecond,
+		}
+
+		report, err := c.ValidateComponents(context.TODO(),
+			mockComponent{name: "hung", validate: hangs},
+			mockComponent{name: "healthy"},
+		)
+		if err != nil {
+			t.Fatalf("expected no err, got %v", err)
+		}
+
+		if status := report.Results[0].Status; status != StatusTimedOut {
+			t.Errorf("expected hung component to be %s, got %s", StatusTimedOut, status)
+		}
+
+		if status := report.Results[1].Status; status != StatusPassed {
+			t.Errorf("expected healthy component to be %s, got %s", StatusPassed, status)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		c := &Client{
+			log:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+			Parallelism: 1,
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		report, err := c.ValidateComponents(ctx,
+			mockComponent{name: "first"},
+			mockComponent{
+				name: "interrupted",
+				validate: func(ctx context.Context) error {
+					cancel()
+					return hangs(ctx)
+				},
+			},
+			mockComponent{name: "last"},
+		)
+		if err != nil {
+			t.Fatalf("expected no err, got %v", err)
+		}
+
+		expected := []Status{StatusPassed, StatusCancelled, StatusNotRun}
+		for i, status := range expected {
+			if report.Results[i].Status != status {
+				t.Errorf("expected %s to be %s, got %s", report.Results[i].Component.Name(), status, report.Results[i].Status)
+			}
+		}
+
+		if !report.Incomplete() {
+			t.Error("expected a cancelled report to be incomplete")
+		}
+	})
+}
+
+func TestClient_ValidateComponents_Errors(t *testing.T) {
+	c := &Client{log: slog.New(slog.NewTextHandler(os.Stdout, nil))}
+
+	report, err := c.ValidateComponents(context.TODO(),
+		mockComponent{name: "unauthorized", validate: returns(&smithy.GenericAPIError{Code: "UnauthorizedOperation"})},
+		mockComponent{name: "misconfigured", validate: returns(errors.Join(Finding{CheckId: "MOCK-001", Severity: SeverityError}, errors.New("api error")))},
+		mockComponent{name: "dependent", deps: []string{"unauthorized"}},
+	)
+	if err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	expected := []struct {
+		status Status
+		reason string
+	}{
+		{status: StatusError, reason: "AWS API error: UnauthorizedOperation"},
+		{status: StatusFailed},
+		{status: StatusSkipped, reason: "blocked by unauthorized"},
+	}
+
+	for i, e := range expected {
+		result := report.Results[i]
+		if result.Status != e.status || result.Reason != e.reason {
+			t.Errorf("expected %s to be %s %q, got %s %q", result.Component.Name(), e.status, e.reason, result.Status, result.Reason)
+		}
+	}
+}
+
+func TestClient_ValidateComponents_Resources(t *testing.T) {
+	c := &Client{
+		log:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		ClusterInfo: &ClusterInfo{Name: "mock", VpcId: "vpc-1"},
+	}
+
+	report, err := c.ValidateComponents(context.TODO(),
+		mockComponent{
+			name: "first",
+			validate: func(ctx context.Context) error {
+				recordResource(ctx, Resource{Type: ResourceTypeVpc, Id: "vpc-1"})
+				recordResource(ctx, Resource{Type: ResourceTypeVpc, Id: "vpc-1"})
+				return nil
+			},
+		},
+		mockComponent{
+			name: "second",
+			validate: func(ctx context.Context) error {
+				recordResource(ctx, Resource{Type: ResourceTypeSecurityGroup, Id: "sg-1", Name: "mock-master-sg", Role: ResourceRoleMaster})
+				return nil
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	expected := []Resource{
+		{Type: ResourceTypeSecurityGroup, Id: "sg-1", Name: "mock-master-sg", Role: ResourceRoleMaster, Component: "second"},
+		{Type: ResourceTypeVpc, Id: "vpc-1", Component: "first"},
+	}
+	if !reflect.DeepEqual(report.Resources, expected) {
+		t.Errorf("expected resources %+v, got %+v", expected, report.Resources)
+	}
+
+	if report.Cluster.VpcId != "vpc-1" {
+		t.Errorf("expected report to include cluster info, got %+v", report.Cluster)
+	}
+}
+
+func TestClient_ValidateComponents_DiscoveredResources(t *testing.T) {
+	c := &Client{
+		log:         slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		ClusterInfo: &ClusterInfo{Name: "mock", VpcId: "vpc-1"},
+		vpc:         Resource{Type: ResourceTypeVpc, Id: "vpc-1", Name: "mock-vpc"},
+		subnets: []Resource{
+			{Type: ResourceTypeSubnet, Id: "subnet-1", Role: ResourceRolePrivate},
+			{Type: ResourceTypeSubnet, Id: "subnet-2", Role: ResourceRolePublic},
+		},
+	}
+
+	// The Vpc is skipped, but the VPC and subnets found while building the Client are still part of the Report
+	report, err := c.ValidateComponents(context.TODO(),
+		mockComponent{name: privateHostedZoneName, validate: returns(Finding{CheckId: "MOCK-001"})},
+		mockComponent{name: vpcName, deps: []string{privateHostedZoneName}},
+	)
+	if err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	if status := report.Results[1].Status; status != StatusSkipped {
+		t.Fatalf("expected %s to be skipped, got %s", vpcName, status)
+	}
+
+	expected := []Resource{
+		{Type: ResourceTypeSubnet, Id: "subnet-1", Role: ResourceRolePrivate, Component: vpcName},
+		{Type: ResourceTypeSubnet, Id: "subnet-2", Role: ResourceRolePublic, Component: vpcName},
+		{Type: ResourceTypeVpc, Id: "vpc-1", Name: "mock-vpc", Component: vpcName},
+	}
+	if !reflect.DeepEqual(report.Resources, expected) {
+		t.Errorf("expected resources %+v, got %+v", expected, report.Resources)
+	}
+}
@@ -0,0 +1,88 @@
+package mirrosa
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// registration pairs a Component's zero value, used for its name and documentation, with its constructor
+type registration struct {
+	component Component
+	new       func(c *Client) Component
+}
+
+// registry is every Component mirrosa knows how to validate, in the order they are displayed
+var registry = []registration{
+	{component: Vpc{}, new: func(c *Client) Component { return c.NewVpc() }},
+	{component: DhcpOptions{}, new: func(c *Client) Component { return c.NewDhcpOptions() }},
+	{component: SecurityGroup{}, new: func(c *Client) Component { return c.NewSecurityGroup() }},
+	{component: VpcEndpointService{}, new: func(c *Client) Component { return c.NewVpcEndpointService() }},
+	{component: PublicHostedZone{}, new: func(c *Client) Component { return c.NewPublicHostedZone() }},
+	{component: PrivateHostedZone{}, new: func(c *Client) Component { return c.NewPrivateHostedZone() }},
+	{component: NetworkLoadBalancer{}, new: func(c *Client) Component { return c.NewApiLoadBalancer() }},
+	{component: Instances{}, new: func(c *Client) Component { return c.NewInstances() }},
+}
+
+// RegisteredComponents returns the zero value of every registered Component. They are only suitable for
+// displaying documentation, use Client.NewComponents to get Components that can be validated.
+func RegisteredComponents() []Component {
+	components := make([]Component, len(registry))
+	for i, r := range registry {
+		components[i] = r.component
+	}
+
+	return components
+}
+
+// ComponentNames returns the name of every registered Component
+func ComponentNames() []string {
+	names := make([]string, len(registry))
+	for i, r := range registry {
+		names[i] = r.component.Name()
+	}
+
+	return names
+}
+
+// NewComponents constructs every registered Component named in only, or all of them if only is empty, except for
+// those named in skip. Unknown names are an error.
+func (c *Client) NewComponents(only, skip []string) ([]Component, error) {
+	selected, err := selectComponents(only, skip)
+	if err != nil {
+		return nil, err
+	}
+
+	components := make([]Component, len(selected))
+	for i, r := range selected {
+		components[i] = r.new(c)
+	}
+
+	return components, nil
+}
+
+// selectComponents returns the registrations named in only, or all of them if only is empty, except for those named
+// in skip, in registry order
+func selectComponents(only, skip []string) ([]registration, error) {
+	names := ComponentNames()
+	for _, name := range append(slices.Clone(only), skip...) {
+		if !slices.Contains(names, name) {
+			return nil, fmt.Errorf("unknown component %q, must be one of: %s", name, strings.Join(names, ", "))
+		}
+	}
+
+	var selected []registration
+	for _, r := range registry {
+		if len(only) > 0 && !slices.Contains(only, r.component.Name()) {
+			continue
+		}
+
+		if slices.Contains(skip, r.component.Name()) {
+			continue
+		}
+
+		selected = append(selected, r)
+	}
+
+	return selected, nil
+}
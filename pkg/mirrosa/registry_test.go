@@ -0,0 +1,66 @@
+package mirrosa
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSelectComponents(t *testing.T) {
+	tests := []struct {
+		name      string
+		only      []string
+		skip      []string
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name:     "all",
+			expected: ComponentNames(),
+		},
+		{
+			name:     "only",
+			only:     []string{instancesName, networkLoadBalancerName},
+			expected: []string{networkLoadBalancerName, instancesName},
+		},
+		{
+			name:     "skip",
+			skip:     []string{vpcName, dhcpOptionsName, securityGroupName, vpcEndpointServiceName, instancesName},
+			expected: []string{publicHostedZoneName, privateHostedZoneName, networkLoadBalancerName},
+		},
+		{
+			name:     "skip takes precedence over only",
+			only:     []string{publicHostedZoneName, privateHostedZoneName},
+			skip:     []string{publicHostedZoneName},
+			expected: []string{privateHostedZoneName},
+		},
+		{
+			name:      "unknown component",
+			only:      []string{"nlb"},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			selected, err := selectComponents(test.only, test.skip)
+			if err != nil {
+				if !test.expectErr {
+					t.Errorf("expected no err, got %v", err)
+				}
+				return
+			}
+			if test.expectErr {
+				t.Fatal("expected err, got nil")
+			}
+
+			var names []string
+			for _, r := range selected {
+				names = append(names, r.component.Name())
+			}
+
+			if !slices.Equal(names, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, names)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package mirrosa
+
+import (
+	"fmt"
+	"time"
+)
+
+// Status summarizes the outcome of validating a Component
+type Status string
+
+const (
+	// StatusPassed means that the Component was fully validated without any findings
+	StatusPassed Status = "passed"
+
+	// StatusWarning means that the Component was fully validated and only has findings with SeverityWarning
+	StatusWarning Status = "warning"
+
+	// StatusFailed means that the Component has findings with SeverityError, i.e. the cluster is misconfigured
+	StatusFailed Status = "failed"
+
+	// StatusError means that the Component could not be fully validated because of an error, e.g. from the AWS API,
+	// but has no findings with SeverityError
+	StatusError Status = "error"
+
+	// StatusSkipped means that the Component was not validated because one of its dependencies did not pass
+	StatusSkipped Status = "skipped"
+
+	// StatusTimedOut means that validating the Component did not finish before its timeout
+	StatusTimedOut Status = "timed-out"
+
+	// StatusCancelled means that the run was cancelled while the Component was being validated
+	StatusCancelled Status = "cancelled"
+
+	// StatusNotRun means that the run was cancelled or timed out before the Component started being validated
+	StatusNotRun Status = "not-run"
+)
+
+// Statuses lists every Status, in the order they are summarized
+var Statuses = []Status{
+	StatusPassed,
+	StatusWarning,
+	StatusFailed,
+	StatusError,
+	StatusSkipped,
+	StatusTimedOut,
+	StatusCancelled,
+	StatusNotRun,
+}
+
+// Result is the outcome of validating a single Component
+type Result struct {
+	Component Component
+
+	// Status summarizes the outcome of validating Component
+	Status Status
+
+	// Reason explains why Component was skipped or did not finish validating
+	Reason string
+
+	// Findings contains every misconfiguration found while validating Component
+	Findings []Finding
+
+	// Err holds any other error, e.g. from the AWS API, that prevented Component from being fully validated
+	Err error
+
+	// Checks contains the outcome of each Check performed by Component
+	Checks []CheckResult
+}
+
+// CheckResult is the outcome of a single Check
+type CheckResult struct {
+	Check Check
+
+	// Status summarizes the outcome of Check
+	Status Status
+
+	// Reason explains why Check was skipped
+	Reason string
+
+	// Findings contains every misconfiguration found by Check
+	Findings []Finding
+}
+
+// Ok returns true if the Component was fully validated without any Findings more severe than a warning
+func (r Result) Ok() bool {
+	return r.Status == StatusPassed || r.Status == StatusWarning
+}
+
+// blocksDependents returns true if the Components that depend on this one must be skipped: because it was skipped
+// or not run itself, because one of its prerequisite Checks did not pass, or, for a Component without Checks,
+// because it did not pass, possibly with warnings. Findings of other Checks never block dependents, so that e.g. a
+// single missing DNS record doesn't hide every other misconfiguration.
+func (r Result) blocksDependents() bool {
+	switch {
+	case r.Status == StatusSkipped || r.Status == StatusNotRun:
+		return true
+	case len(r.Checks) == 0:
+		return !r.Ok()
+	}
+
+	for _, check := range r.Checks {
+		if check.Check.Prerequisite && check.Status != StatusPassed && check.Status != StatusWarning {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Report contains the Result of validating each Component, in topological order of their dependencies
+type Report struct {
+	// Cluster is the information about the cluster that was validated
+	Cluster ClusterInfo
+
+	// Started is when validation started
+	Started time.Time
+
+	// Duration is how long validating every Component took
+	Duration time.Duration
+
+	Results []Result
+
+	// Resources contains every AWS resource discovered while validating the cluster
+	Resources []Resource
+
+	// ApiCalls summarizes the AWS API calls made during the run, including those made to discover the cluster
+	ApiCalls []ApiCallStats
+}
+
+// HasFindings returns true if any Component has a Finding at least as severe as failOn
+func (r Report) HasFindings(failOn Severity) bool {
+	for _, result := range r.Results {
+		for _, f := range result.Findings {
+			if failOn == SeverityWarning || f.Severity != SeverityWarning {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Incomplete returns true if any Component could not be evaluated, e.g. because of an AWS API error or a timeout
+func (r Report) Incomplete() bool {
+	for _, result := range r.Results {
+		switch result.Status {
+		case StatusError, StatusTimedOut, StatusCancelled, StatusNotRun:
+			return true
+		}
+	}
+
+	return false
+}
+
+// Count returns the number of Components with the given Status
+func (r Report) Count(status Status) int {
+	n := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			n++
+		}
+	}
+
+	return n
+}
+
+// checkResults determines the outcome of each Check given the Findings and other error returned by Component.Validate.
+// A Check without Findings is skipped rather than passed if the checks it requires did not pass or if err prevented
+// the Component from being fully validated.
+func checkResults(checks []Check, findings []Finding, err error) []CheckResult {
+	var (
+		results  = make([]CheckResult, 0, len(checks))
+		statuses = make(map[string]Status, len(checks))
+	)
+
+	for _, check := range checks {
+		result := CheckResult{Check: check, Status: StatusPassed}
+		for _, f := range findings {
+			if f.CheckId == check.Id {
+				result.Findings = append(result.Findings, f)
+			}
+		}
+
+		switch blocker := blockingCheck(check, statuses); {
+		case len(result.Findings) > 0:
+			result.Status = findingsStatus(result.Findings)
+		case blocker != "":
+			result.Status = StatusSkipped
+			result.Reason = fmt.Sprintf("blocked by %s", blocker)
+		case err != nil:
+			result.Status = StatusSkipped
+			result.Reason = fmt.Sprintf("could not be evaluated: %s", err)
+		}
+
+		statuses[check.Id] = result.Status
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// blockingCheck returns the Id of the first check required by check that did not pass, or an empty string
+func blockingCheck(check Check, statuses map[string]Status) string {
+	for _, id := range check.Requires {
+		if statuses[id] != StatusPassed && statuses[id] != StatusWarning {
+			return id
+		}
+	}
+
+	return ""
+}
+
+// findingsStatus returns StatusWarning if every Finding has SeverityWarning, otherwise StatusFailed
+func findingsStatus(findings []Finding) Status {
+	for _, f := range findings {
+		if f.Severity != SeverityWarning {
+			return StatusFailed
+		}
+	}
+
+	return StatusWarning
+}
+
+// skipChecks marks every Check as skipped for reason
+func skipChecks(checks []Check, reason string) []CheckResult {
+	results := make([]CheckResult, 0, len(checks))
+	for _, check := range checks {
+		results = append(results, CheckResult{Check: check, Status: StatusSkipped, Reason: reason})
+	}
+
+	return results
+}
+
+// unwrapErrors flattens errors combined with errors.Join so that each one can be reported individually
+func unwrapErrors(err error) []error {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, e := range joined.Unwrap() {
+		errs = append(errs, unwrapErrors(e)...)
+	}
+
+	return errs
+}
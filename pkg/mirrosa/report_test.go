@@ -0,0 +1,129 @@
+package mirrosa
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckResults(t *testing.T) {
+	checks := []Check{
+		{Id: "MOCK-001"},
+		{Id: "MOCK-002", Requires: []string{"MOCK-001"}},
+		{Id: "MOCK-003"},
+	}
+
+	tests := []struct {
+		name     string
+		findings []Finding
+		err      error
+		expected []Status
+	}{
+		{
+			name:     "all passed",
+			expected: []Status{StatusPassed, StatusPassed, StatusPassed},
+		},
+		{
+			name:     "required check failed",
+			findings: []Finding{{CheckId: "MOCK-001"}},
+			expected: []Status{StatusFailed, StatusSkipped, StatusPassed},
+		},
+		{
+			name:     "findings are reported even if a required check failed",
+			findings: []Finding{{CheckId: "MOCK-001"}, {CheckId: "MOCK-002"}},
+			expected: []Status{StatusFailed, StatusFailed, StatusPassed},
+		},
+		{
+			name:     "warnings do not block required checks",
+			findings: []Finding{{CheckId: "MOCK-001", Severity: SeverityWarning}},
+			expected: []Status{StatusWarning, StatusPassed, StatusPassed},
+		},
+		{
+			name:     "errors take precedence over warnings",
+			findings: []Finding{{CheckId: "MOCK-003", Severity: SeverityWarning}, {CheckId: "MOCK-003", Severity: SeverityError}},
+			expected: []Status{StatusPassed, StatusPassed, StatusFailed},
+		},
+		{
+			name:     "not fully evaluated",
+			findings: []Finding{{CheckId: "MOCK-003"}},
+			err:      errors.New("api error"),
+			expected: []Status{StatusSkipped, StatusSkipped, StatusFailed},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			results := checkResults(checks, test.findings, test.err)
+			if len(results) != len(test.expected) {
+				t.Fatalf("expected %d results, got %d", len(test.expected), len(results))
+			}
+
+			for i, result := range results {
+				if result.Status != test.expected[i] {
+					t.Errorf("%s: expected %s, got %s", result.Check.Id, test.expected[i], result.Status)
+				}
+			}
+		})
+	}
+}
+
+func TestReport_HasFindings(t *testing.T) {
+	tests := []struct {
+		name             string
+		results          []Result
+		expectWarning    bool
+		expectError      bool
+		expectIncomplete bool
+	}{
+		{
+			name:    "healthy",
+			results: []Result{{Status: StatusPassed}},
+		},
+		{
+			name:          "warning",
+			results:       []Result{{Status: StatusWarning, Findings: []Finding{{Severity: SeverityWarning}}}},
+			expectWarning: true,
+		},
+		{
+			name: "findings and errors",
+			results: []Result{
+				{Status: StatusFailed, Findings: []Finding{{Severity: SeverityError}}},
+				{Status: StatusError},
+			},
+			expectWarning:    true,
+			expectError:      true,
+			expectIncomplete: true,
+		},
+		{
+			name:             "not run",
+			results:          []Result{{Status: StatusPassed}, {Status: StatusNotRun}},
+			expectIncomplete: true,
+		},
+		{
+			name:             "cancelled",
+			results:          []Result{{Status: StatusCancelled}},
+			expectIncomplete: true,
+		},
+		{
+			name:             "timed out",
+			results:          []Result{{Status: StatusTimedOut}},
+			expectIncomplete: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report := Report{Results: test.results}
+			if actual := report.HasFindings(SeverityWarning); actual != test.expectWarning {
+				t.Errorf("warning: expected %t, got %t", test.expectWarning, actual)
+			}
+
+			if actual := report.HasFindings(SeverityError); actual != test.expectError {
+				t.Errorf("error: expected %t, got %t", test.expectError, actual)
+			}
+
+			if actual := report.Incomplete(); actual != test.expectIncomplete {
+				t.Errorf("incomplete: expected %t, got %t", test.expectIncomplete, actual)
+			}
+		})
+	}
+}
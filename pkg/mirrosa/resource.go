@@ -0,0 +1,145 @@
+package mirrosa
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// Types of AWS resources that components discover, named after their CloudFormation resource types
+const (
+	ResourceTypeVpc                = "AWS::EC2::VPC"
+	ResourceTypeSubnet             = "AWS::EC2::Subnet"
+	ResourceTypeDhcpOptions        = "AWS::EC2::DHCPOptions"
+	ResourceTypeSecurityGroup      = "AWS::EC2::SecurityGroup"
+	ResourceTypeInstance           = "AWS::EC2::Instance"
+	ResourceTypeVpcEndpointService = "AWS::EC2::VPCEndpointService"
+	ResourceTypeLoadBalancer       = "AWS::ElasticLoadBalancingV2::LoadBalancer"
+	ResourceTypeTargetGroup        = "AWS::ElasticLoadBalancingV2::TargetGroup"
+	ResourceTypeHostedZone         = "AWS::Route53::HostedZone"
+)
+
+// Roles that a Resource plays in the cluster
+const (
+	ResourceRoleMaster   = "master"
+	ResourceRoleInfra    = "infra"
+	ResourceRoleWorker   = "worker"
+	ResourceRoleInternal = "int"
+	ResourceRoleExternal = "ext"
+	ResourceRolePublic   = "public"
+	ResourceRolePrivate  = "private"
+)
+
+// Resource is an AWS resource that was discovered while validating a cluster
+type Resource struct {
+	// Type is the CloudFormation resource type, e.g. AWS::EC2::VPC
+	Type string `json:"type"`
+
+	// Id is the AWS ID of the resource, or its ARN if it is identified by one
+	Id string `json:"id"`
+
+	// Name is the value of the resource's Name tag, or its name if it is not tagged, e.g. for a load balancer
+	Name string `json:"name,omitempty"`
+
+	// Role is the role that the resource plays in the cluster, e.g. master, int, or public, if it has one
+	Role string `json:"role,omitempty"`
+
+	// Component is the Name of the Component that discovered the resource
+	Component string `json:"component"`
+}
+
+// resourceCollector gathers the Resources discovered by every component in a run
+type resourceCollector struct {
+	mu        sync.Mutex
+	resources map[Resource]struct{}
+}
+
+type resourceCollectorKey struct{}
+
+type resourceComponentKey struct{}
+
+// withResourceCollector returns a context that collects the Resources recorded with it into rc
+func withResourceCollector(ctx context.Context, rc *resourceCollector) context.Context {
+	return context.WithValue(ctx, resourceCollectorKey{}, rc)
+}
+
+// withResourceComponent returns a context that attributes the Resources recorded with it to component
+func withResourceComponent(ctx context.Context, component string) context.Context {
+	return context.WithValue(ctx, resourceComponentKey{}, component)
+}
+
+// recordResource records that resource was discovered, attributed to the Component validated with ctx. It does nothing
+// if ctx is not collecting Resources, e.g. when a Component is validated on its own.
+func recordResource(ctx context.Context, resource Resource) {
+	rc, ok := ctx.Value(resourceCollectorKey{}).(*resourceCollector)
+	if !ok || resource.Id == "" {
+		return
+	}
+
+	resource.Component, _ = ctx.Value(resourceComponentKey{}).(string)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.resources == nil {
+		rc.resources = map[Resource]struct{}{}
+	}
+	rc.resources[resource] = struct{}{}
+}
+
+// list returns every collected Resource, sorted by type, id, and component
+func (rc *resourceCollector) list() []Resource {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	resources := make([]Resource, 0, len(rc.resources))
+	for r := range rc.resources {
+		resources = append(resources, r)
+	}
+	slices.SortFunc(resources, func(a, b Resource) int {
+		return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(a.Id, b.Id), cmp.Compare(a.Component, b.Component))
+	})
+
+	return resources
+}
+
+// ec2NameTag returns the value of the Name tag of an EC2 resource, or an empty string if it is not tagged
+func ec2NameTag(tags []types.Tag) string {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) == "Name" {
+			return aws.ToString(tag.Value)
+		}
+	}
+
+	return ""
+}
+
+// subnetRole returns whether a subnet is public or private from the tags the installer uses to place load balancers
+func subnetRole(tags []types.Tag) string {
+	for _, tag := range tags {
+		switch aws.ToString(tag.Key) {
+		case "kubernetes.io/role/elb":
+			return ResourceRolePublic
+		case "kubernetes.io/role/internal-elb":
+			return ResourceRolePrivate
+		}
+	}
+
+	return ""
+}
+
+// instanceRole returns whether an instance is a master, infra, or worker node from its Name tag
+func instanceRole(infraName, name string) string {
+	for _, role := range []string{ResourceRoleMaster, ResourceRoleInfra, ResourceRoleWorker} {
+		if strings.Contains(name, fmt.Sprintf("%s-%s", infraName, role)) {
+			return role
+		}
+	}
+
+	return ""
+}
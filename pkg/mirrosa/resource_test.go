@@ -0,0 +1,60 @@
+package mirrosa
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestInstanceRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "mock-abcde-master-0", expected: ResourceRoleMaster},
+		{name: "mock-abcde-infra-us-east-1a-x7z2k", expected: ResourceRoleInfra},
+		{name: "mock-abcde-worker-us-east-1a-qv4d9", expected: ResourceRoleWorker},
+		{name: "mock-abcde-worker-sg", expected: ResourceRoleWorker},
+		{name: "bastion", expected: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := instanceRole("mock-abcde", test.name); actual != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSubnetRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     []types.Tag
+		expected string
+	}{
+		{
+			name:     "public",
+			tags:     []types.Tag{{Key: aws.String("Name"), Value: aws.String("mock-public")}, {Key: aws.String("kubernetes.io/role/elb"), Value: aws.String("")}},
+			expected: ResourceRolePublic,
+		},
+		{
+			name:     "private",
+			tags:     []types.Tag{{Key: aws.String("kubernetes.io/role/internal-elb"), Value: aws.String("")}},
+			expected: ResourceRolePrivate,
+		},
+		{
+			name:     "untagged",
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := subnetRole(test.tags); actual != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
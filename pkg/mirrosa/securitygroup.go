@@ -2,6 +2,7 @@ package mirrosa
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 
@@ -20,6 +21,15 @@ const securityGroupDescription = "Security groups act as a virtual firewall for
 //"\n  - Inbound master 10259 kube-scheduler from worker" +
 //"\n  - Inbound master 10250 kubelet from worker"
 
+const (
+	securityGroupName = "security-groups"
+
+	securityGroupExistsCheckId        = "MIRROSA-SG-001"
+	securityGroupEtcdRuleCheckId      = "MIRROSA-SG-002"
+	securityGroupApiServerRuleCheckId = "MIRROSA-SG-003"
+	securityGroupWorkerExistsCheckId  = "MIRROSA-SG-004"
+)
+
 type securityGroupRule struct {
 	CidrIpv4   string
 	IpProtocol types.Protocol
@@ -28,6 +38,15 @@ type securityGroupRule struct {
 	IsEgress   bool
 }
 
+func (r securityGroupRule) String() string {
+	direction := "inbound"
+	if r.IsEgress {
+		direction = "outbound"
+	}
+
+	return fmt.Sprintf("%s %s %d-%d from %s", direction, r.IpProtocol, r.FromPort, r.ToPort, r.CidrIpv4)
+}
+
 var _ Component = &SecurityGroup{}
 
 type SecurityGroup struct {
@@ -40,10 +59,10 @@ type SecurityGroup struct {
 
 func (c *Client) NewSecurityGroup() SecurityGroup {
 	return SecurityGroup{
-		log:         c.log,
+		log:         c.log.With(slog.String("component", securityGroupName)),
 		InfraName:   c.ClusterInfo.InfraName,
 		MachineCIDR: c.Cluster.Network().MachineCIDR(),
-		Ec2Client:   ec2.NewFromConfig(c.AwsConfig),
+		Ec2Client:   c.awsClients().ec2,
 	}
 }
 
@@ -58,6 +77,12 @@ func (s SecurityGroup) Validate(ctx context.Context) error {
 		workerGroup: "",
 	}
 
+	groupCheckIds := map[string]string{
+		masterGroup: securityGroupExistsCheckId,
+		workerGroup: securityGroupWorkerExistsCheckId,
+	}
+
+	var errs []error
 	for group := range expectedGroups {
 		s.log.Info("searching for security group", slog.String("name", group))
 		resp, err := s.Ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
@@ -73,20 +98,45 @@ func (s SecurityGroup) Validate(ctx context.Context) error {
 			},
 		})
 		if err != nil {
-			return err
+			return errors.Join(append(errs, err)...)
 		}
 
 		switch len(resp.SecurityGroups) {
 		case 0:
-			return fmt.Errorf("security group: %s not found", group)
+			errs = append(errs, Finding{
+				CheckId:    groupCheckIds[group],
+				ResourceId: group,
+				Expected:   "1 security group",
+				Actual:     "0 security groups",
+				Severity:   SeverityError,
+				Message:    fmt.Sprintf("security group: %s not found", group),
+			})
 		case 1:
 			s.log.Info("found security group", slog.String("name", group), slog.String("id", *resp.SecurityGroups[0].GroupId))
 			expectedGroups[group] = *resp.SecurityGroups[0].GroupId
+			recordResource(ctx, Resource{
+				Type: ResourceTypeSecurityGroup,
+				Id:   expectedGroups[group],
+				Name: group,
+				Role: instanceRole(s.InfraName, group),
+			})
 		default:
-			return fmt.Errorf("multiple matches found for security group: %s", group)
+			errs = append(errs, Finding{
+				CheckId:    groupCheckIds[group],
+				ResourceId: group,
+				Expected:   "1 security group",
+				Actual:     fmt.Sprintf("%d security groups", len(resp.SecurityGroups)),
+				Severity:   SeverityError,
+				Message:    fmt.Sprintf("multiple matches found for security group: %s", group),
+			})
 		}
 	}
 
+	// The master security group's rules can't be validated if it can't be identified
+	if expectedGroups[masterGroup] == "" {
+		return errors.Join(errs...)
+	}
+
 	s.log.Info("validating security group rules inside master security group")
 	resp, err := s.Ec2Client.DescribeSecurityGroupRules(ctx, &ec2.DescribeSecurityGroupRulesInput{
 		Filters: []types.Filter{
@@ -97,10 +147,14 @@ func (s SecurityGroup) Validate(ctx context.Context) error {
 		},
 	})
 	if err != nil {
-		return err
+		return errors.Join(append(errs, err)...)
 	}
 
 	// TODO: Validate more rules?
+	ruleCheckIds := map[string]string{
+		"etcd":           securityGroupEtcdRuleCheckId,
+		"kube-apiserver": securityGroupApiServerRuleCheckId,
+	}
 	expectedMasterRules := map[string]securityGroupRule{
 		"etcd": {
 			CidrIpv4:   s.MachineCIDR,
@@ -133,11 +187,43 @@ func (s SecurityGroup) Validate(ctx context.Context) error {
 		}
 	}
 
-	if len(expectedMasterRules) > 0 {
-		return fmt.Errorf("missing required rules in master security group %v", expectedMasterRules)
+	for k, missing := range expectedMasterRules {
+		errs = append(errs, Finding{
+			CheckId:    ruleCheckIds[k],
+			ResourceId: expectedGroups[masterGroup],
+			Expected:   missing.String(),
+			Actual:     "no matching rule",
+			Severity:   SeverityError,
+			Message:    fmt.Sprintf("missing required %s rule in master security group: %s", k, expectedGroups[masterGroup]),
+		})
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+func (s SecurityGroup) Name() string {
+	return securityGroupName
+}
+
+func (s SecurityGroup) Dependencies() []string {
+	return []string{vpcName}
+}
+
+func (s SecurityGroup) Checks() []Check {
+	return []Check{
+		{Id: securityGroupExistsCheckId, Title: "Control plane security group exists"},
+		{Id: securityGroupWorkerExistsCheckId, Title: "Worker security group exists"},
+		{
+			Id:       securityGroupEtcdRuleCheckId,
+			Title:    "Control plane security group allows etcd from the machine CIDR",
+			Requires: []string{securityGroupExistsCheckId},
+		},
+		{
+			Id:       securityGroupApiServerRuleCheckId,
+			Title:    "Control plane security group allows kube-apiserver from the machine CIDR",
+			Requires: []string{securityGroupExistsCheckId},
+		},
+	}
 }
 
 func (s SecurityGroup) Description() string {
@@ -0,0 +1,121 @@
+package mirrosa
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// ApiCallStats summarizes the calls made to a single AWS API operation during a run
+type ApiCallStats struct {
+	// Service is the AWS service ID, e.g. EC2
+	Service string `json:"service"`
+
+	// Operation is the name of the AWS API operation, e.g. DescribeVpcs
+	Operation string `json:"operation"`
+
+	// Calls is the number of calls that were sent to AWS
+	Calls int `json:"calls"`
+
+	// Cached is the number of calls that were answered from the describeCache without calling AWS
+	Cached int `json:"cached"`
+
+	// Retries is the number of attempts that were retried, including those that were throttled
+	Retries int `json:"retries"`
+
+	// Throttles is the number of attempts that AWS throttled
+	Throttles int `json:"throttles"`
+}
+
+// apiCallKey identifies an AWS API operation
+type apiCallKey struct {
+	service   string
+	operation string
+}
+
+// apiCallRecorder counts AWS API calls, retries, and throttles per operation
+type apiCallRecorder struct {
+	mu    sync.Mutex
+	stats map[apiCallKey]*ApiCallStats
+}
+
+func newApiCallRecorder() *apiCallRecorder {
+	return &apiCallRecorder{stats: map[apiCallKey]*ApiCallStats{}}
+}
+
+// record updates the ApiCallStats of the operation being called in ctx
+func (r *apiCallRecorder) record(ctx context.Context, update func(s *ApiCallStats)) {
+	key := apiCallKey{
+		service:   middleware.GetServiceID(ctx),
+		operation: middleware.GetOperationName(ctx),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.stats[key]
+	if !ok {
+		s = &ApiCallStats{Service: key.service, Operation: key.operation}
+		r.stats[key] = s
+	}
+	update(s)
+}
+
+// snapshot returns the ApiCallStats of every operation called so far, sorted by service and operation
+func (r *apiCallRecorder) snapshot() []ApiCallStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := make([]ApiCallStats, 0, len(r.stats))
+	for _, s := range r.stats {
+		stats = append(stats, *s)
+	}
+	slices.SortFunc(stats, func(a, b ApiCallStats) int {
+		return cmp.Or(cmp.Compare(a.Service, b.Service), cmp.Compare(a.Operation, b.Operation))
+	})
+
+	return stats
+}
+
+// ID implements middleware.InitializeMiddleware
+func (r *apiCallRecorder) ID() string {
+	return "MirrosaApiCallRecorder"
+}
+
+// HandleInitialize implements middleware.InitializeMiddleware, counting a call and using the retry middleware's
+// results to count its retries and throttles
+func (r *apiCallRecorder) HandleInitialize(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+	out, metadata, err := next.HandleInitialize(ctx, in)
+
+	attempts, _ := retry.GetAttemptResults(metadata)
+	r.record(ctx, func(s *ApiCallStats) {
+		s.Calls++
+		for _, attempt := range attempts.Results {
+			if attempt.Retried {
+				s.Retries++
+			}
+
+			if attempt.Err != nil && retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(attempt.Err) == aws.TrueTernary {
+				s.Throttles++
+			}
+		}
+	})
+
+	return out, metadata, err
+}
+
+// addToStack adds the recorder to the start of an AWS API client's middleware stack, for use in
+// aws.Config.APIOptions. It must be added before a describeCache so that cached calls are not counted as calls.
+func (r *apiCallRecorder) addToStack(stack *middleware.Stack) error {
+	return stack.Initialize.Add(r, middleware.Before)
+}
+
+// recordCached counts a call answered by a describeCache
+func (r *apiCallRecorder) recordCached(ctx context.Context) {
+	r.record(ctx, func(s *ApiCallStats) { s.Cached++ })
+}
@@ -0,0 +1,57 @@
+package mirrosa
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+func TestApiCallRecorder(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+
+		// Throttle the first request only
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`<Response><Errors><Error><Code>RequestLimitExceeded</Code><Message>Request limit exceeded.</Message></Error></Errors><RequestID>mock</RequestID></Response>`))
+			return
+		}
+
+		w.Write([]byte(`<DescribeVpcsResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><vpcSet/></DescribeVpcsResponse>`))
+	}))
+	defer server.Close()
+
+	clients := newAwsClients(aws.Config{
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(server.URL),
+	}, Options{})
+
+	for range 2 {
+		if _, err := clients.ec2.DescribeVpcs(context.TODO(), &ec2.DescribeVpcsInput{}); err != nil {
+			t.Fatalf("expected no err, got %v", err)
+		}
+	}
+
+	expected := []ApiCallStats{
+		{
+			Service:   "EC2",
+			Operation: "DescribeVpcs",
+			Calls:     1,
+			Cached:    1,
+			Retries:   1,
+			Throttles: 1,
+		},
+	}
+
+	if actual := clients.recorder.snapshot(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %+v, got %+v", expected, actual)
+	}
+}
@@ -6,6 +6,12 @@ import (
 
 // Component represents a specific component that will be validated
 type Component interface {
+	// Name returns a short, stable identifier for the component, e.g. to tag its log lines
+	Name() string
+
+	// Dependencies returns the Name of each component that must pass validation before this one is validated
+	Dependencies() []string
+
 	// FilterValue returns the name of the component to implement the github.com/charmbracelet/bubbles/list Item interface
 	FilterValue() string
 
@@ -16,6 +22,30 @@ type Component interface {
 	// It should allow a new user of ROSA to understand what the expected state is and why it should be that way.
 	Description() string
 
-	// Validate checks a component for any misconfiguration and returns any error
+	// Checks returns every Check performed by Validate, in the order they are performed
+	Checks() []Check
+
+	// Validate checks a component for any misconfiguration and returns any error.
+	// Misconfigurations are returned as a Finding, or several joined together with errors.Join.
 	Validate(ctx context.Context) error
 }
+
+// Check is a single rule validated by a Component. Each Check is reported individually, so that runbooks and
+// suppressions can refer to exactly which rule failed.
+type Check struct {
+	// Id is a stable identifier for the check, e.g. MIRROSA-SG-002, which is used as the CheckId of its Findings
+	Id string `json:"id"`
+
+	// Title is a short summary of what the check validates
+	Title string `json:"title"`
+
+	// Requires lists the Id of each earlier check in the same Component that must pass for this one to be evaluated
+	Requires []string `json:"requires,omitempty"`
+
+	// Prerequisite marks a check that must pass for the Components that depend on this one to be validated, e.g.
+	// that a resource exists at all. Other checks of a Component never block its dependents.
+	Prerequisite bool `json:"prerequisite,omitempty"`
+
+	// Severity is the most severe Severity of the Findings the check can report, SeverityError if empty
+	Severity Severity `json:"severity,omitempty"`
+}
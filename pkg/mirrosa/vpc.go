@@ -2,6 +2,7 @@ package mirrosa
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 
@@ -23,6 +24,13 @@ const vpcDescription = "A VPC is a logically isolated network in AWS and a ROSA
 	"3. https://docs.openshift.com/rosa/rosa_planning/rosa-sts-aws-prereqs.html#osd-aws-privatelink-firewall-prerequisites_rosa-sts-aws-prereqs\n" +
 	"4. https://github.com/openshift/osd-network-verifier"
 
+const (
+	vpcName = "vpc"
+
+	vpcDnsHostnamesCheckId = "MIRROSA-VPC-001"
+	vpcDnsSupportCheckId   = "MIRROSA-VPC-002"
+)
+
 // Ensure Vpc implements Component
 var _ Component = &Vpc{}
 
@@ -40,14 +48,15 @@ type Vpc struct {
 
 func (c *Client) NewVpc() Vpc {
 	return Vpc{
-		log:       c.log,
+		log:       c.log.With(slog.String("component", vpcName)),
 		Id:        c.ClusterInfo.VpcId,
-		Ec2Client: ec2.NewFromConfig(c.AwsConfig),
+		Ec2Client: c.awsClients().ec2,
 	}
 }
 
 func (v Vpc) Validate(ctx context.Context) error {
 	v.log.Info("validating vpc", slog.String("id", v.Id))
+	var errs []error
 
 	v.log.Debug("validating that enableDnsHostnames is true", slog.String("id", v.Id))
 	dnsHostnames, err := v.Ec2Client.DescribeVpcAttribute(ctx, &ec2.DescribeVpcAttributeInput{
@@ -59,7 +68,14 @@ func (v Vpc) Validate(ctx context.Context) error {
 	}
 
 	if !*dnsHostnames.EnableDnsHostnames.Value {
-		return fmt.Errorf("enableDnsHostnames is false for VPC: %s", v.Id)
+		errs = append(errs, Finding{
+			CheckId:    vpcDnsHostnamesCheckId,
+			ResourceId: v.Id,
+			Expected:   "enableDnsHostnames: true",
+			Actual:     "enableDnsHostnames: false",
+			Severity:   SeverityError,
+			Message:    fmt.Sprintf("enableDnsHostnames is false for VPC: %s", v.Id),
+		})
 	}
 
 	v.log.Debug("validating that enableDnsSupport is true", slog.String("id", v.Id))
@@ -68,16 +84,38 @@ func (v Vpc) Validate(ctx context.Context) error {
 		VpcId:     aws.String(v.Id),
 	})
 	if err != nil {
-		return err
+		return errors.Join(append(errs, err)...)
 	}
 
 	if !*dnsSupport.EnableDnsSupport.Value {
-		return fmt.Errorf("enableDnsSupport is false for VPC: %s", v.Id)
+		errs = append(errs, Finding{
+			CheckId:    vpcDnsSupportCheckId,
+			ResourceId: v.Id,
+			Expected:   "enableDnsSupport: true",
+			Actual:     "enableDnsSupport: false",
+			Severity:   SeverityError,
+			Message:    fmt.Sprintf("enableDnsSupport is false for VPC: %s", v.Id),
+		})
 	}
 
+	return errors.Join(errs...)
+}
+
+func (v Vpc) Name() string {
+	return vpcName
+}
+
+func (v Vpc) Dependencies() []string {
 	return nil
 }
 
+func (v Vpc) Checks() []Check {
+	return []Check{
+		{Id: vpcDnsHostnamesCheckId, Title: "enableDnsHostnames is enabled"},
+		{Id: vpcDnsSupportCheckId, Title: "enableDnsSupport is enabled"},
+	}
+}
+
 func (v Vpc) Description() string {
 	return vpcDescription
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"os"
+	"reflect"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -20,9 +21,11 @@ func (m mockMirrosaVpcAPI) DescribeVpcAttribute(ctx context.Context, params *ec2
 
 func TestVpc_Validate(t *testing.T) {
 	tests := []struct {
-		name    string
-		client  func(t *testing.T) MirrosaVpcAPIClient
-		wantErr bool
+		name         string
+		client       func(t *testing.T) MirrosaVpcAPIClient
+		wantErr      bool
+		wantFindings []string
+		wantApiErr   bool
 	}{
 		{
 			name: "enableDnsSupport and enableDnsHostnames true",
@@ -70,7 +73,27 @@ func TestVpc_Validate(t *testing.T) {
 					return nil, errors.New("unsupported attribute")
 				})
 			},
-			wantErr: true,
+			wantErr:      true,
+			wantFindings: []string{vpcDnsSupportCheckId},
+		},
+		{
+			name: "findings are kept when a later call fails",
+			client: func(t *testing.T) MirrosaVpcAPIClient {
+				return mockMirrosaVpcAPI(func(ctx context.Context, params *ec2.DescribeVpcAttributeInput, optFns ...func(options *ec2.Options)) (*ec2.DescribeVpcAttributeOutput, error) {
+					t.Helper()
+					if params.Attribute == types.VpcAttributeNameEnableDnsHostnames {
+						return &ec2.DescribeVpcAttributeOutput{
+							EnableDnsHostnames: &types.AttributeBooleanValue{Value: aws.Bool(false)},
+							VpcId:              params.VpcId,
+						}, nil
+					}
+
+					return nil, errors.New("api error")
+				})
+			},
+			wantErr:      true,
+			wantFindings: []string{vpcDnsHostnamesCheckId},
+			wantApiErr:   true,
 		},
 	}
 
@@ -81,9 +104,23 @@ func TestVpc_Validate(t *testing.T) {
 				Id:        "id",
 				Ec2Client: test.client(t),
 			}
-			if err := v.Validate(context.TODO()); (err != nil) != test.wantErr {
+			err := v.Validate(context.TODO())
+			if (err != nil) != test.wantErr {
 				t.Errorf("Validate() error = %v, wantErr %v", err, test.wantErr)
 			}
+
+			findings, apiErr := splitFindings(err)
+			var checkIds []string
+			for _, f := range findings {
+				checkIds = append(checkIds, f.CheckId)
+			}
+			if !reflect.DeepEqual(checkIds, test.wantFindings) {
+				t.Errorf("expected findings %v, got %v", test.wantFindings, checkIds)
+			}
+
+			if (apiErr != nil) != test.wantApiErr {
+				t.Errorf("expected api error %t, got %v", test.wantApiErr, apiErr)
+			}
 		})
 	}
 }
@@ -2,7 +2,6 @@ package mirrosa
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -17,6 +16,13 @@ const vpceServiceDescription = "A VPC Endpoint Service allows for a load balance
 	"1. https://docs.aws.amazon.com/vpc/latest/privatelink/privatelink-share-your-services.html\n" +
 	"2. https://github.com/openshift/hive/tree/master/pkg/controller/awsprivatelink"
 
+const (
+	vpcEndpointServiceName = "vpc-endpoint-service"
+
+	vpceServiceExistsCheckId     = "MIRROSA-VPCE-001"
+	vpceServiceConnectionCheckId = "MIRROSA-VPCE-002"
+)
+
 var _ Component = &VpcEndpointService{}
 
 // MirrosaVpcEndpointServiceAPIClient is a client that implements what's needed to validate a VpcEndpointService
@@ -35,10 +41,10 @@ type VpcEndpointService struct {
 
 func (c *Client) NewVpcEndpointService() VpcEndpointService {
 	return VpcEndpointService{
-		log:         c.log,
+		log:         c.log.With(slog.String("component", vpcEndpointServiceName)),
 		InfraName:   c.ClusterInfo.InfraName,
 		PrivateLink: c.Cluster.AWS().PrivateLink(),
-		Ec2Client:   ec2.NewFromConfig(c.AwsConfig),
+		Ec2Client:   c.awsClients().ec2,
 	}
 }
 
@@ -48,13 +54,14 @@ func (v VpcEndpointService) Validate(ctx context.Context) error {
 		return nil
 	}
 
-	v.log.Info("searching for PrivateLink VPC Endpoint Service", slog.String("name", fmt.Sprintf("%s-vpc-endpoint-service", v.InfraName)))
+	expectedName := fmt.Sprintf("%s-vpc-endpoint-service", v.InfraName)
+	v.log.Info("searching for PrivateLink VPC Endpoint Service", slog.String("name", expectedName))
 	var serviceId string
 	resp, err := v.Ec2Client.DescribeVpcEndpointServices(ctx, &ec2.DescribeVpcEndpointServicesInput{
 		Filters: []types.Filter{
 			{
 				Name:   aws.String("tag:Name"),
-				Values: []string{fmt.Sprintf("%s-vpc-endpoint-service", v.InfraName)},
+				Values: []string{expectedName},
 			},
 			{
 				Name:   aws.String("tag:hive.openshift.io/private-link-access-for"),
@@ -68,12 +75,27 @@ func (v VpcEndpointService) Validate(ctx context.Context) error {
 
 	switch len(resp.ServiceDetails) {
 	case 0:
-		return errors.New("no VPC Endpoint Services found for PrivateLink cluster")
+		return Finding{
+			CheckId:    vpceServiceExistsCheckId,
+			ResourceId: expectedName,
+			Expected:   "1 VPC Endpoint Service",
+			Actual:     "0 VPC Endpoint Services",
+			Severity:   SeverityError,
+			Message:    "no VPC Endpoint Services found for PrivateLink cluster",
+		}
 	case 1:
 		v.log.Info("found VPC Endpoint Service", slog.String("id", *resp.ServiceDetails[0].ServiceId))
 		serviceId = *resp.ServiceDetails[0].ServiceId
+		recordResource(ctx, Resource{Type: ResourceTypeVpcEndpointService, Id: serviceId, Name: expectedName})
 	default:
-		return errors.New("multiple VPC Endpoint Services found for PrivateLink cluster")
+		return Finding{
+			CheckId:    vpceServiceExistsCheckId,
+			ResourceId: expectedName,
+			Expected:   "1 VPC Endpoint Service",
+			Actual:     fmt.Sprintf("%d VPC Endpoint Services", len(resp.ServiceDetails)),
+			Severity:   SeverityError,
+			Message:    "multiple VPC Endpoint Services found for PrivateLink cluster",
+		}
 	}
 
 	v.log.Info("validating VPC Endpoint Service", slog.String("id", *resp.ServiceDetails[0].ServiceId))
@@ -95,12 +117,50 @@ func (v VpcEndpointService) Validate(ctx context.Context) error {
 
 	switch len(cxResp.VpcEndpointConnections) {
 	case 0:
-		return fmt.Errorf("no available VPC Endpoint connections found for %s", serviceId)
+		return Finding{
+			CheckId:    vpceServiceConnectionCheckId,
+			ResourceId: serviceId,
+			Expected:   "1 available VPC Endpoint connection",
+			Actual:     "0 available VPC Endpoint connections",
+			Severity:   SeverityError,
+			Message:    fmt.Sprintf("no available VPC Endpoint connections found for %s", serviceId),
+		}
 	case 1:
 		v.log.Info("validated that one accepted VPC Endpoint connection exists", slog.String("id", serviceId))
 		return nil
 	default:
-		return fmt.Errorf("multiple available VPC Endpoint connections found for %s", serviceId)
+		return Finding{
+			CheckId:    vpceServiceConnectionCheckId,
+			ResourceId: serviceId,
+			Expected:   "1 available VPC Endpoint connection",
+			Actual:     fmt.Sprintf("%d available VPC Endpoint connections", len(cxResp.VpcEndpointConnections)),
+			Severity:   SeverityError,
+			Message:    fmt.Sprintf("multiple available VPC Endpoint connections found for %s", serviceId),
+		}
+	}
+}
+
+func (v VpcEndpointService) Name() string {
+	return vpcEndpointServiceName
+}
+
+func (v VpcEndpointService) Dependencies() []string {
+	return nil
+}
+
+func (v VpcEndpointService) Checks() []Check {
+	// non-PrivateLink clusters do not have a VPC Endpoint Service
+	if !v.PrivateLink {
+		return nil
+	}
+
+	return []Check{
+		{Id: vpceServiceExistsCheckId, Title: "VPC Endpoint Service exists"},
+		{
+			Id:       vpceServiceConnectionCheckId,
+			Title:    "VPC Endpoint Service has 1 available VPC Endpoint connection",
+			Requires: []string{vpceServiceExistsCheckId},
+		},
 	}
 }
 
@@ -3,6 +3,7 @@ package ocm
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -11,6 +12,7 @@ import (
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/backplane-cli/cmd/ocm-backplane/cloud"
 	bpconfig "github.com/openshift/backplane-cli/pkg/cli/config"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -51,6 +53,28 @@ func GetCluster(conn *sdk.Connection, clusterId string) (*cmv1.Cluster, error) {
 	return nil, fmt.Errorf("there are %d clusters with identifier or name '%s', expected 1", clustersTotal, clusterId)
 }
 
+// ReadClusterFile returns an OCM cluster object from a JSON or YAML file at path, e.g. the output of
+// `ocm get cluster <id>`, so that a cluster can be validated without an OCM connection
+func ReadClusterFile(path string) (*cmv1.Cluster, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON is valid YAML, so both are converted to JSON for the OCM SDK
+	data, err = yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cluster file %s: %w", path, err)
+	}
+
+	cluster, err := cmv1.UnmarshalCluster(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cluster file %s: %w", path, err)
+	}
+
+	return cluster, nil
+}
+
 // GetCloudCredentials sets up AWS credentials via backplane-api given a cluster id, OCM token, and backplane-api URL
 func GetCloudCredentials(conn *sdk.Connection, cluster *cmv1.Cluster) (aws.Config, error) {
 	bp, err := bpconfig.GetBackplaneConfiguration()
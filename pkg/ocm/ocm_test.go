@@ -0,0 +1,54 @@
+package ocm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadClusterFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		expectErr bool
+	}{
+		{
+			name: "json",
+			data: `{"kind": "Cluster", "id": "mock-id", "name": "mock", "infra_id": "mock-abcde", "aws": {"private_link": true}}`,
+		},
+		{
+			name: "yaml",
+			data: "kind: Cluster\nid: mock-id\nname: mock\ninfra_id: mock-abcde\naws:\n  private_link: true\n",
+		},
+		{
+			name:      "invalid",
+			data:      "kind: [Cluster",
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cluster")
+			if err := os.WriteFile(path, []byte(test.data), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			cluster, err := ReadClusterFile(path)
+			if err != nil {
+				if !test.expectErr {
+					t.Fatalf("expected no err, got %v", err)
+				}
+				return
+			}
+
+			if test.expectErr {
+				t.Fatal("expected err, got nil")
+			}
+
+			if cluster.ID() != "mock-id" || cluster.Name() != "mock" || cluster.InfraID() != "mock-abcde" || !cluster.AWS().PrivateLink() {
+				t.Errorf("expected mock cluster, got id %q name %q infra id %q", cluster.ID(), cluster.Name(), cluster.InfraID())
+			}
+		})
+	}
+}
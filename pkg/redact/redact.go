@@ -0,0 +1,213 @@
+// Package redact pseudonymizes identifying information in mirrosa's output so that reports and evidence can be shared
+// outside of the account they describe. Pseudonyms are derived from an HMAC of the original value, so every occurrence
+// of a value is replaced by the same pseudonym and cross-references between resources stay intact.
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// accountIdPattern matches AWS account IDs, which are exactly 12 digits
+	accountIdPattern = regexp.MustCompile(`\b\d{12}\b`)
+
+	// hostedZoneIdPattern matches Route53 hosted zone IDs
+	hostedZoneIdPattern = regexp.MustCompile(`\bZ[0-9A-Z]{8,31}\b`)
+
+	// ipv4Pattern matches candidate IPv4 addresses, which are then parsed to decide if they are public
+	ipv4Pattern = regexp.MustCompile(`\b\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}\b`)
+
+	// pseudonymIpPrefix is the benchmarking range that public IP addresses are mapped into, it is large enough that
+	// collisions are unlikely and is never routed on the internet
+	pseudonymIpPrefix = netip.MustParsePrefix("198.18.0.0/15")
+)
+
+// Redactor replaces AWS account IDs, public IP addresses, Route53 hosted zone IDs, and cluster names with stable
+// pseudonyms. ARNs are redacted by way of the account IDs and names they contain.
+type Redactor struct {
+	key   []byte
+	names []string
+}
+
+// New returns a Redactor that derives pseudonyms from key, so the same key always produces the same pseudonyms. If key
+// is empty, a random key is used and pseudonyms are only stable for the lifetime of the Redactor. Every non-empty
+// string in names, e.g. a cluster's name, infra ID, and OCM IDs, is also pseudonymized wherever it stands on its own.
+func New(key string, names ...string) (*Redactor, error) {
+	r := &Redactor{key: []byte(key)}
+	if key == "" {
+		r.key = make([]byte, 32)
+		if _, err := rand.Read(r.key); err != nil {
+			return nil, fmt.Errorf("failed to generate redaction key: %w", err)
+		}
+	}
+
+	for _, name := range names {
+		if name != "" && !slices.Contains(r.names, name) {
+			r.names = append(r.names, name)
+		}
+	}
+
+	// Replace longer names first so that a name containing another, e.g. an infra ID that starts with the cluster's
+	// name, gets its own pseudonym
+	slices.SortFunc(r.names, func(a, b string) int {
+		return len(b) - len(a)
+	})
+
+	return r, nil
+}
+
+// String returns s with every identifying value replaced by its pseudonym. A nil Redactor returns s unchanged.
+func (r *Redactor) String(s string) string {
+	if r == nil {
+		return s
+	}
+
+	s = r.replaceNames(s)
+
+	s = replaceIsolated(s, accountIdPattern, func(id string) string {
+		return fmt.Sprintf("%012d", binary.BigEndian.Uint64(r.sum(id))%1_000_000_000_000)
+	})
+
+	s = hostedZoneIdPattern.ReplaceAllStringFunc(s, func(id string) string {
+		return "Z" + strings.ToUpper(r.hash(id)[:len(id)-1])
+	})
+
+	s = replaceIsolated(s, ipv4Pattern, func(ip string) string {
+		addr, err := netip.ParseAddr(ip)
+		if err != nil || !isPublic(addr) {
+			return ip
+		}
+
+		base := pseudonymIpPrefix.Addr().As4()
+		offset := binary.BigEndian.Uint32(r.sum(ip)) % (1 << (32 - pseudonymIpPrefix.Bits()))
+		return netip.AddrFrom4([4]byte{
+			base[0] | byte(offset>>24),
+			base[1] | byte(offset>>16),
+			base[2] | byte(offset>>8),
+			base[3] | byte(offset),
+		}).String()
+	})
+
+	return s
+}
+
+// Bytes is String for byte slices
+func (r *Redactor) Bytes(b []byte) []byte {
+	return []byte(r.String(string(b)))
+}
+
+// sum returns the HMAC of value
+func (r *Redactor) sum(value string) []byte {
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+// hash returns the hex-encoded HMAC of value
+func (r *Redactor) hash(value string) string {
+	return hex.EncodeToString(r.sum(value))
+}
+
+// replaceNames replaces every occurrence of r.names in s that stands on its own, rather than being part of a longer
+// identifier, DNS label, ARN field, or word, with its pseudonym. This keeps short names like "vpc" or "aws" from
+// mangling vpc-0123456789abcdef0, arn:aws:, or product. Names that contain a "-", e.g. infra IDs, are specific enough
+// to also be replaced where resources are named after them, e.g. mock-abcde-master-sg. Names used as JSON object keys
+// are kept so that redacted JSON keeps its schema.
+func (r *Redactor) replaceNames(s string) string {
+	if len(r.names) == 0 {
+		return s
+	}
+
+	var (
+		b    strings.Builder
+		last int
+	)
+
+	for start := 0; start < len(s); start++ {
+		for _, name := range r.names {
+			end := start + len(name)
+			if !strings.HasPrefix(s[start:], name) || isJsonKey(s, start, end) {
+				continue
+			}
+
+			hyphenated := strings.Contains(name, "-")
+			if (start > 0 && !isNameBoundary(s[start-1], hyphenated)) || (end < len(s) && !isNameBoundary(s[end], hyphenated)) {
+				continue
+			}
+
+			b.WriteString(s[last:start])
+			b.WriteString("redacted-" + r.hash(name)[:8])
+			last = end
+			start = end - 1
+			break
+		}
+	}
+	b.WriteString(s[last:])
+
+	return b.String()
+}
+
+// isNameBoundary returns true if c may come directly before or after a name. A "-" only separates hyphenated names
+// from the rest of a resource's name.
+func isNameBoundary(c byte, hyphenated bool) bool {
+	switch {
+	case c == '-':
+		return hyphenated
+	case c == '_' || c == ':':
+		return false
+	default:
+		return !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9')
+	}
+}
+
+// isJsonKey returns true if s[start:end] is a quoted JSON object key
+func isJsonKey(s string, start, end int) bool {
+	if start == 0 || s[start-1] != '"' || end >= len(s) || s[end] != '"' {
+		return false
+	}
+
+	return strings.HasPrefix(strings.TrimLeft(s[end+1:], " \t\r\n"), ":")
+}
+
+// replaceIsolated replaces every match of re in s that isn't part of a larger number, e.g. the fractional part of a
+// duration, with the result of replace
+func replaceIsolated(s string, re *regexp.Regexp, replace func(string) string) string {
+	var (
+		b    strings.Builder
+		last int
+	)
+
+	for _, loc := range re.FindAllStringIndex(s, -1) {
+		start, end := loc[0], loc[1]
+		if (start > 0 && isNumeric(s[start-1])) || (end < len(s) && isNumeric(s[end])) {
+			continue
+		}
+
+		b.WriteString(s[last:start])
+		b.WriteString(replace(s[start:end]))
+		last = end
+	}
+	b.WriteString(s[last:])
+
+	return b.String()
+}
+
+// isNumeric returns true for the characters that numbers are made of
+func isNumeric(c byte) bool {
+	return c == '.' || (c >= '0' && c <= '9')
+}
+
+// isPublic returns true if addr is a globally routable address
+func isPublic(addr netip.Addr) bool {
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !pseudonymIpPrefix.Contains(addr) &&
+		!netip.MustParsePrefix("100.64.0.0/10").Contains(addr)
+}
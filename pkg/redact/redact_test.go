@@ -0,0 +1,145 @@
+package redact
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRedactor_String(t *testing.T) {
+	r, err := New("mock-key", "mock", "mock-abcde")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		redacted  []string
+		preserved []string
+	}{
+		{
+			name:      "arn",
+			input:     "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/mock-abcde-int/0123456789abcdef",
+			redacted:  []string{"123456789012", "mock-abcde"},
+			preserved: []string{"arn:aws:elasticloadbalancing:us-east-1:", ":loadbalancer/net/", "-int/0123456789abcdef"},
+		},
+		{
+			name:      "cluster name",
+			input:     `{"name": "mock", "infraName": "mock-abcde"}`,
+			redacted:  []string{`"mock"`, "mock-abcde"},
+			preserved: []string{`"name": "redacted-`},
+		},
+		{
+			name:     "hosted zone",
+			input:    "/hostedzone/Z0123456789ABCDEFGHIJ",
+			redacted: []string{"Z0123456789ABCDEFGHIJ"},
+		},
+		{
+			name:      "ip addresses",
+			input:     "public 3.5.140.2 private 10.0.0.1 cidr 10.0.0.0/16",
+			redacted:  []string{"3.5.140.2"},
+			preserved: []string{"10.0.0.1", "10.0.0.0/16", "198.1"},
+		},
+		{
+			name:      "numbers",
+			input:     `"durationSeconds": 1.123456789012, "id": 1234567890123`,
+			preserved: []string{"1.123456789012", "1234567890123"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := r.String(test.input)
+			for _, s := range test.redacted {
+				if strings.Contains(actual, s) {
+					t.Errorf("expected %q to be redacted, got %s", s, actual)
+				}
+			}
+
+			for _, s := range test.preserved {
+				if !strings.Contains(actual, s) {
+					t.Errorf("expected %q to be preserved, got %s", s, actual)
+				}
+			}
+
+			if again := r.String(test.input); again != actual {
+				t.Errorf("expected stable pseudonyms, got %s and %s", actual, again)
+			}
+		})
+	}
+}
+
+func TestRedactor_ShortNames(t *testing.T) {
+	r, err := New("mock-key", "vpc", "aws", "prod", "vpc-abcde")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		redacted  []string
+		preserved []string
+	}{
+		{
+			name:      "json key",
+			input:     `{"vpc": "vpc", "vpcId": "vpc-0123456789abcdef0", "infraName": "vpc-abcde"}`,
+			redacted:  []string{`"vpc",`, `"vpc-abcde"`},
+			preserved: []string{`{"vpc": "redacted-`, `"vpcId": "vpc-0123456789abcdef0"`, `"infraName": "redacted-`},
+		},
+		{
+			name:      "arn",
+			input:     "arn:aws:ec2:us-east-1:123456789012:security-group/sg-0123456789abcdef0 vpc-abcde-master-sg",
+			redacted:  []string{"vpc-abcde"},
+			preserved: []string{"arn:aws:ec2:", "-master-sg"},
+		},
+		{
+			name:      "words",
+			input:     "product prod-1 prod_1 prod.example.com (prod)",
+			redacted:  []string{" prod.", "(prod)"},
+			preserved: []string{"product", "prod-1", "prod_1", ".example.com", "(redacted-"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := r.String(test.input)
+			for _, s := range test.redacted {
+				if strings.Contains(actual, s) {
+					t.Errorf("expected %q to be redacted, got %s", s, actual)
+				}
+			}
+
+			for _, s := range test.preserved {
+				if !strings.Contains(actual, s) {
+					t.Errorf("expected %q to be preserved, got %s", s, actual)
+				}
+			}
+
+			if strings.HasPrefix(test.input, "{") && !json.Valid([]byte(actual)) {
+				t.Errorf("expected valid JSON, got %s", actual)
+			}
+		})
+	}
+}
+
+func TestRedactor_StableAcrossRedactors(t *testing.T) {
+	input := "123456789012 /hostedzone/Z0123456789ABCDEFGHIJ mock"
+
+	a, _ := New("mock-key", "mock")
+	b, _ := New("mock-key", "mock")
+	if a.String(input) != b.String(input) {
+		t.Errorf("expected the same key to produce the same pseudonyms, got %s and %s", a.String(input), b.String(input))
+	}
+
+	c, _ := New("other-key", "mock")
+	if a.String(input) == c.String(input) {
+		t.Errorf("expected different keys to produce different pseudonyms, got %s", a.String(input))
+	}
+
+	var nilRedactor *Redactor
+	if nilRedactor.String(input) != input {
+		t.Error("expected a nil Redactor to not redact anything")
+	}
+}
@@ -0,0 +1,222 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+// graphRenderers maps each supported graph format to its Renderer
+var graphRenderers = map[string]Renderer{
+	"mermaid": Mermaid,
+	"dot":     Dot,
+}
+
+// GraphFormats returns the name of every supported graph format, sorted
+func GraphFormats() []string {
+	formats := make([]string, 0, len(graphRenderers))
+	for format := range graphRenderers {
+		formats = append(formats, format)
+	}
+	slices.Sort(formats)
+
+	return formats
+}
+
+// Graph writes the graph of components and AWS resources discovered in r to w in the given format
+func Graph(w io.Writer, format string, r mirrosa.Report) error {
+	render, ok := graphRenderers[format]
+	if !ok {
+		return fmt.Errorf("unsupported graph format %q, must be one of: %s", format, strings.Join(GraphFormats(), ", "))
+	}
+
+	return render(w, r)
+}
+
+// graphClass is how a node is colored in a graph
+type graphClass string
+
+const (
+	graphPassed  graphClass = "passed"
+	graphWarning graphClass = "warning"
+	graphFailed  graphClass = "failed"
+	graphSkipped graphClass = "skipped"
+)
+
+// graphColors are the fill and stroke colors of each graphClass
+var graphColors = map[graphClass][2]string{
+	graphPassed:  {"#d4edda", "#28a745"},
+	graphWarning: {"#fff3cd", "#ffc107"},
+	graphFailed:  {"#f8d7da", "#dc3545"},
+	graphSkipped: {"#e2e3e5", "#6c757d"},
+}
+
+// graphStatusClass colors components that could not be validated like failed ones, since they are also where to look
+// for breakage, and components that were never validated grey
+func graphStatusClass(status mirrosa.Status) graphClass {
+	switch status {
+	case mirrosa.StatusPassed:
+		return graphPassed
+	case mirrosa.StatusWarning:
+		return graphWarning
+	case mirrosa.StatusSkipped, mirrosa.StatusCancelled, mirrosa.StatusNotRun:
+		return graphSkipped
+	default:
+		return graphFailed
+	}
+}
+
+// graphNode is a component, an AWS resource, or the cluster itself
+type graphNode struct {
+	id    string
+	label string
+	class graphClass
+}
+
+// graphEdge points from a node to a node that depends on it or was discovered by it
+type graphEdge struct {
+	from string
+	to   string
+}
+
+// buildGraph returns the nodes and edges of the graph of r. The cluster points to the components without
+// dependencies, each component points to the components that depend on it and to the AWS resources it discovered.
+func buildGraph(r mirrosa.Report) ([]graphNode, []graphEdge) {
+	nodes := []graphNode{{id: "cluster", label: fmt.Sprintf("Cluster\n%s", r.Cluster.Name)}}
+	var edges []graphEdge
+
+	componentIds := map[string]string{}
+	for i, result := range r.Results {
+		componentIds[result.Component.Name()] = fmt.Sprintf("c%d", i)
+	}
+
+	for i, result := range r.Results {
+		id := componentIds[result.Component.Name()]
+		class := graphStatusClass(result.Status)
+		nodes = append(nodes, graphNode{id: id, label: result.Component.Title(), class: class})
+
+		var hasDependency bool
+		for _, dep := range result.Component.Dependencies() {
+			if depId, ok := componentIds[dep]; ok {
+				edges = append(edges, graphEdge{from: depId, to: id})
+				hasDependency = true
+			}
+		}
+		if !hasDependency {
+			edges = append(edges, graphEdge{from: "cluster", to: id})
+		}
+
+		for j, resource := range r.Resources {
+			if resource.Component != result.Component.Name() {
+				continue
+			}
+
+			resourceId := fmt.Sprintf("c%dr%d", i, j)
+			nodes = append(nodes, graphNode{id: resourceId, label: resourceLabel(resource), class: resourceClass(result, resource)})
+			edges = append(edges, graphEdge{from: id, to: resourceId})
+		}
+	}
+
+	return nodes, edges
+}
+
+// resourceClass colors a resource by the Findings of its component that are about it, rather than by the outcome of
+// the whole component, so that the broken resource stands out among the ones that are fine. Resources of a component
+// that could not finish validating are left uncolored unless they have Findings, since their state is unknown.
+func resourceClass(result mirrosa.Result, resource mirrosa.Resource) graphClass {
+	var class graphClass
+	for _, f := range result.Findings {
+		if f.ResourceId != resource.Id {
+			continue
+		}
+
+		if f.Severity != mirrosa.SeverityWarning {
+			return graphFailed
+		}
+		class = graphWarning
+	}
+
+	if class != "" || result.Err != nil {
+		return class
+	}
+
+	switch result.Status {
+	case mirrosa.StatusPassed, mirrosa.StatusWarning, mirrosa.StatusFailed:
+		return graphPassed
+	default:
+		return ""
+	}
+}
+
+// resourceLabel labels a resource with the last part of its type, e.g. VPC for AWS::EC2::VPC, and its id
+func resourceLabel(resource mirrosa.Resource) string {
+	t := resource.Type
+	if i := strings.LastIndex(t, "::"); i >= 0 {
+		t = t[i+2:]
+	}
+
+	return fmt.Sprintf("%s\n%s", t, resource.Id)
+}
+
+// Mermaid writes the graph of components and AWS resources discovered in r as a Mermaid flowchart
+func Mermaid(w io.Writer, r mirrosa.Report) error {
+	nodes, edges := buildGraph(r)
+
+	var b strings.Builder
+	b.WriteString("graph TD;\n")
+	for _, node := range nodes {
+		fmt.Fprintf(&b, "  %s[\"%s\"]", node.id, mermaidLabel(node.label))
+		if node.class != "" {
+			fmt.Fprintf(&b, ":::%s", node.class)
+		}
+		b.WriteString("\n")
+	}
+
+	for _, edge := range edges {
+		fmt.Fprintf(&b, "  %s-->%s\n", edge.from, edge.to)
+	}
+
+	for _, class := range []graphClass{graphPassed, graphWarning, graphFailed, graphSkipped} {
+		fmt.Fprintf(&b, "  classDef %s fill:%s,stroke:%s\n", class, graphColors[class][0], graphColors[class][1])
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// mermaidLabel escapes s for a quoted Mermaid node label
+func mermaidLabel(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", "<br/>").Replace(s)
+}
+
+// Dot writes the graph of components and AWS resources discovered in r in the Graphviz DOT language
+func Dot(w io.Writer, r mirrosa.Report) error {
+	nodes, edges := buildGraph(r)
+
+	var b strings.Builder
+	b.WriteString("digraph mirrosa {\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=\"#ffffff\"];\n")
+	for _, node := range nodes {
+		fmt.Fprintf(&b, "  %s [label=%s", node.id, dotString(node.label))
+		if colors, ok := graphColors[node.class]; ok {
+			fmt.Fprintf(&b, ", fillcolor=%s, color=%s", dotString(colors[0]), dotString(colors[1]))
+		}
+		b.WriteString("];\n")
+	}
+
+	for _, edge := range edges {
+		fmt.Fprintf(&b, "  %s -> %s;\n", edge.from, edge.to)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotString quotes s as a DOT string
+func dotString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,180 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+func TestGraph(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		expected  []string
+		expectErr bool
+	}{
+		{
+			name:   "mermaid",
+			format: "mermaid",
+			expected: []string{
+				"graph TD;",
+				`c0["HEALTHY"]:::passed`,
+				`c1["BROKEN"]:::failed`,
+				`c1r0["VPC<br/>vpc-1"]:::failed`,
+				"cluster-->c0",
+				"c0-->c1",
+				"c1-->c1r0",
+				"classDef failed",
+			},
+		},
+		{
+			name:   "dot",
+			format: "dot",
+			expected: []string{
+				"digraph mirrosa {",
+				`c1r0 [label="VPC\nvpc-1", fillcolor="#f8d7da"`,
+				"cluster -> c0;",
+				"c0 -> c1;",
+				"c1 -> c1r0;",
+			},
+		},
+		{
+			name:      "unsupported",
+			format:    "svg",
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Graph(&buf, test.format, mockReport())
+			if err != nil {
+				if !test.expectErr {
+					t.Fatalf("expected no err, got %v", err)
+				}
+				return
+			}
+
+			if test.expectErr {
+				t.Fatal("expected err, got nil")
+			}
+
+			for _, s := range test.expected {
+				if !strings.Contains(buf.String(), s) {
+					t.Errorf("expected %q in graph, got %s", s, buf.String())
+				}
+			}
+
+			if strings.Contains(buf.String(), "cluster-->c1") || strings.Contains(buf.String(), "cluster -> c1;") {
+				t.Errorf("expected components with dependencies to not hang off the cluster, got %s", buf.String())
+			}
+		})
+	}
+}
+
+func TestResourceClass(t *testing.T) {
+	resources := []mirrosa.Resource{
+		{Type: mirrosa.ResourceTypeInstance, Id: "i-1", Component: "instances"},
+		{Type: mirrosa.ResourceTypeInstance, Id: "i-2", Component: "instances"},
+		{Type: mirrosa.ResourceTypeInstance, Id: "i-3", Component: "instances"},
+	}
+
+	tests := []struct {
+		name     string
+		result   mirrosa.Result
+		expected []graphClass
+	}{
+		{
+			name:     "passed",
+			result:   mirrosa.Result{Status: mirrosa.StatusPassed},
+			expected: []graphClass{graphPassed, graphPassed, graphPassed},
+		},
+		{
+			name: "one failing resource",
+			result: mirrosa.Result{
+				Status:   mirrosa.StatusFailed,
+				Findings: []mirrosa.Finding{{ResourceId: "i-2", Severity: mirrosa.SeverityError}},
+			},
+			expected: []graphClass{graphPassed, graphFailed, graphPassed},
+		},
+		{
+			name: "errors take precedence over warnings",
+			result: mirrosa.Result{
+				Status: mirrosa.StatusFailed,
+				Findings: []mirrosa.Finding{
+					{ResourceId: "i-1", Severity: mirrosa.SeverityWarning},
+					{ResourceId: "i-2", Severity: mirrosa.SeverityWarning},
+					{ResourceId: "i-2", Severity: mirrosa.SeverityError},
+				},
+			},
+			expected: []graphClass{graphWarning, graphFailed, graphPassed},
+		},
+		{
+			name: "finding about the cluster",
+			result: mirrosa.Result{
+				Status:   mirrosa.StatusFailed,
+				Findings: []mirrosa.Finding{{ResourceId: "mock-abcde", Severity: mirrosa.SeverityError}},
+			},
+			expected: []graphClass{graphPassed, graphPassed, graphPassed},
+		},
+		{
+			name: "could not finish validating",
+			result: mirrosa.Result{
+				Status:   mirrosa.StatusFailed,
+				Findings: []mirrosa.Finding{{ResourceId: "i-3", Severity: mirrosa.SeverityError}},
+				Err:      errors.New("api error"),
+			},
+			expected: []graphClass{"", "", graphFailed},
+		},
+		{
+			name:     "timed out",
+			result:   mirrosa.Result{Status: mirrosa.StatusTimedOut},
+			expected: []graphClass{"", "", ""},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i, resource := range resources {
+				if actual := resourceClass(test.result, resource); actual != test.expected[i] {
+					t.Errorf("%s: expected %q, got %q", resource.Id, test.expected[i], actual)
+				}
+			}
+		})
+	}
+}
+
+func TestMermaid_OneFailingResource(t *testing.T) {
+	r := mirrosa.Report{
+		Results: []mirrosa.Result{{
+			Component: mockComponent{name: "instances"},
+			Status:    mirrosa.StatusFailed,
+			Findings:  []mirrosa.Finding{{ResourceId: "i-2", Severity: mirrosa.SeverityError}},
+		}},
+		Resources: []mirrosa.Resource{
+			{Type: mirrosa.ResourceTypeInstance, Id: "i-1", Component: "instances"},
+			{Type: mirrosa.ResourceTypeInstance, Id: "i-2", Component: "instances"},
+			{Type: mirrosa.ResourceTypeInstance, Id: "i-3", Component: "instances"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Mermaid(&buf, r); err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	for _, s := range []string{
+		`c0["INSTANCES"]:::failed`,
+		`c0r0["Instance<br/>i-1"]:::passed`,
+		`c0r1["Instance<br/>i-2"]:::failed`,
+		`c0r2["Instance<br/>i-3"]:::passed`,
+	} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("expected %q in graph, got %s", s, buf.String())
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package report
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+//go:embed html.tmpl
+var htmlTemplate string
+
+// htmlReport is rendered with htmlTemplate
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"status": func(status mirrosa.Status) string {
+		return strings.ToUpper(string(status))
+	},
+	"ok": func(result mirrosa.Result) bool {
+		return result.Ok()
+	},
+}).Parse(htmlTemplate))
+
+// HTML writes r to w as a single, self-contained HTML document with the same content as Markdown. Styles are inlined
+// and there are no external assets, so the file can be attached to a support ticket as-is.
+func HTML(w io.Writer, r mirrosa.Report) error {
+	return htmlReport.Execute(w, r)
+}
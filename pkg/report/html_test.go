@@ -0,0 +1,82 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name       string
+		report     func() mirrosa.Report
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name:     "mock report",
+			report:   mockReport,
+			expected: []string{"<!DOCTYPE html>", "<style>", "mock description", "<code>vpc-1</code>", "Could not finish validating: api error"},
+		},
+		{
+			name: "findings are escaped",
+			report: func() mirrosa.Report {
+				r := mockReport()
+				r.Results[1].Findings[0].Actual = "<script>alert(1)</script>"
+				return r
+			},
+			expected:   []string{"&lt;script&gt;"},
+			unexpected: []string{"<script>"},
+		},
+		{
+			name: "cluster name is escaped",
+			report: func() mirrosa.Report {
+				r := mockReport()
+				r.Cluster.Name = `mock"><img src=x>`
+				return r
+			},
+			expected:   []string{"mirrosa report for mock&#34;&gt;&lt;img src=x&gt;"},
+			unexpected: []string{"<img"},
+		},
+		{
+			name:       "only problems are expanded",
+			report:     mockReport,
+			expected:   []string{"<details>\n  <summary>About HEALTHY</summary>", "<details open>\n  <summary>About BROKEN</summary>"},
+			unexpected: []string{"<details open>\n  <summary>About HEALTHY</summary>"},
+		},
+		{
+			name: "warnings are collapsed",
+			report: func() mirrosa.Report {
+				r := mockReport()
+				r.Results[1].Status = mirrosa.StatusWarning
+				r.Results[1].Err = nil
+				return r
+			},
+			expected:   []string{`<span class="status warning">WARNING</span>`, "<details>\n  <summary>About BROKEN</summary>"},
+			unexpected: []string{"<details open>", "Could not finish validating"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := HTML(&buf, test.report()); err != nil {
+				t.Fatalf("expected no err, got %v", err)
+			}
+
+			for _, s := range test.expected {
+				if !strings.Contains(buf.String(), s) {
+					t.Errorf("expected report to contain %q, got %s", s, buf.String())
+				}
+			}
+
+			for _, s := range test.unexpected {
+				if strings.Contains(buf.String(), s) {
+					t.Errorf("expected report to not contain %q, got %s", s, buf.String())
+				}
+			}
+		})
+	}
+}
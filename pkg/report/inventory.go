@@ -0,0 +1,78 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+// inventoryRenderers maps each supported inventory format to its Renderer
+var inventoryRenderers = map[string]Renderer{
+	"csv":  InventoryCSV,
+	"json": InventoryJSON,
+}
+
+// InventoryFormats returns the name of every supported inventory format, sorted
+func InventoryFormats() []string {
+	formats := make([]string, 0, len(inventoryRenderers))
+	for format := range inventoryRenderers {
+		formats = append(formats, format)
+	}
+	slices.Sort(formats)
+
+	return formats
+}
+
+// Inventory writes every AWS resource discovered in r to w in the given format
+func Inventory(w io.Writer, format string, r mirrosa.Report) error {
+	render, ok := inventoryRenderers[format]
+	if !ok {
+		return fmt.Errorf("unsupported inventory format %q, must be one of: %s", format, strings.Join(InventoryFormats(), ", "))
+	}
+
+	return render(w, r)
+}
+
+// inventoryColumns is the header of InventoryCSV
+var inventoryColumns = []string{"type", "id", "name", "role", "component"}
+
+// InventoryCSV writes every AWS resource discovered in r to w as CSV with a header row
+func InventoryCSV(w io.Writer, r mirrosa.Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(inventoryColumns); err != nil {
+		return err
+	}
+
+	for _, resource := range r.Resources {
+		if err := cw.Write([]string{resource.Type, resource.Id, resource.Name, resource.Role, resource.Component}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// jsonInventory is the machine-readable form of the resources discovered in a mirrosa.Report
+type jsonInventory struct {
+	SchemaVersion int                 `json:"schemaVersion"`
+	Cluster       mirrosa.ClusterInfo `json:"cluster"`
+	Resources     []mirrosa.Resource  `json:"resources"`
+}
+
+// InventoryJSON writes the cluster and every AWS resource discovered in r to w as an indented JSON document
+func InventoryJSON(w io.Writer, r mirrosa.Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(jsonInventory{
+		SchemaVersion: jsonSchemaVersion,
+		Cluster:       r.Cluster,
+		Resources:     nonNil(r.Resources),
+	})
+}
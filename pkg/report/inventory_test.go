@@ -0,0 +1,114 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+func TestInventory(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		report    mirrosa.Report
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "csv",
+			format:   "csv",
+			report:   mockReport(),
+			expected: "type,id,name,role,component\nAWS::EC2::VPC,vpc-1,mock-abcde-vpc,,broken\n",
+		},
+		{
+			name:     "csv without resources",
+			format:   "csv",
+			report:   mirrosa.Report{},
+			expected: "type,id,name,role,component\n",
+		},
+		{
+			name:   "csv quotes names",
+			format: "csv",
+			report: mirrosa.Report{Resources: []mirrosa.Resource{
+				{Type: mirrosa.ResourceTypeSubnet, Id: "subnet-1", Name: `mock, "private"`, Role: mirrosa.ResourceRolePrivate, Component: "vpc"},
+			}},
+			expected: "type,id,name,role,component\nAWS::EC2::Subnet,subnet-1,\"mock, \"\"private\"\"\",private,vpc\n",
+		},
+		{
+			name:      "unsupported",
+			format:    "xlsx",
+			report:    mockReport(),
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Inventory(&buf, test.format, test.report)
+			if err != nil {
+				if !test.expectErr {
+					t.Fatalf("expected no err, got %v", err)
+				}
+				return
+			}
+
+			if test.expectErr {
+				t.Fatal("expected err, got nil")
+			}
+
+			if buf.String() != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestInventoryJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		report   mirrosa.Report
+		expected string
+	}{
+		{
+			name:     "resources",
+			report:   mockReport(),
+			expected: `"infraName": "mock-abcde"`,
+		},
+		{
+			name:     "without resources",
+			report:   mirrosa.Report{},
+			expected: `"resources": []`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := InventoryJSON(&buf, test.report); err != nil {
+				t.Fatalf("expected no err, got %v", err)
+			}
+
+			var actual jsonInventory
+			if err := json.Unmarshal(buf.Bytes(), &actual); err != nil {
+				t.Fatalf("expected valid JSON, got %v", err)
+			}
+
+			if actual.SchemaVersion != jsonSchemaVersion {
+				t.Errorf("expected schema version %d, got %d", jsonSchemaVersion, actual.SchemaVersion)
+			}
+
+			if len(test.report.Resources) > 0 && !reflect.DeepEqual(actual.Resources, test.report.Resources) {
+				t.Errorf("expected %v, got %v", test.report.Resources, actual.Resources)
+			}
+
+			if !strings.Contains(buf.String(), test.expected) {
+				t.Errorf("expected %q in inventory, got %s", test.expected, buf.String())
+			}
+		})
+	}
+}
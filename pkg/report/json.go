@@ -0,0 +1,100 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+// jsonSchemaVersion is incremented whenever a field of the JSON report is changed or removed
+const jsonSchemaVersion = 1
+
+// jsonReport is the machine-readable form of a mirrosa.Report
+type jsonReport struct {
+	SchemaVersion int                    `json:"schemaVersion"`
+	Cluster       mirrosa.ClusterInfo    `json:"cluster"`
+	StartTime     time.Time              `json:"startTime"`
+	Duration      float64                `json:"durationSeconds"`
+	Summary       map[mirrosa.Status]int `json:"summary"`
+	Components    []jsonComponent        `json:"components"`
+	Resources     []mirrosa.Resource     `json:"resources"`
+	ApiCalls      []mirrosa.ApiCallStats `json:"apiCalls"`
+}
+
+// jsonComponent is the machine-readable form of a mirrosa.Result
+type jsonComponent struct {
+	Name     string            `json:"name"`
+	Title    string            `json:"title"`
+	Status   mirrosa.Status    `json:"status"`
+	Reason   string            `json:"reason,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Findings []mirrosa.Finding `json:"findings"`
+	Checks   []jsonCheck       `json:"checks"`
+}
+
+// jsonCheck is the machine-readable form of a mirrosa.CheckResult
+type jsonCheck struct {
+	mirrosa.Check
+	Status   mirrosa.Status    `json:"status"`
+	Reason   string            `json:"reason,omitempty"`
+	Findings []mirrosa.Finding `json:"findings"`
+}
+
+// JSON writes the complete Report to w as an indented JSON document. Lists are never null so that consumers don't
+// need to distinguish between missing and empty values.
+func JSON(w io.Writer, r mirrosa.Report) error {
+	out := jsonReport{
+		SchemaVersion: jsonSchemaVersion,
+		Cluster:       r.Cluster,
+		StartTime:     r.Started,
+		Duration:      r.Duration.Seconds(),
+		Summary:       map[mirrosa.Status]int{},
+		Components:    []jsonComponent{},
+		Resources:     nonNil(r.Resources),
+		ApiCalls:      nonNil(r.ApiCalls),
+	}
+
+	for _, status := range mirrosa.Statuses {
+		out.Summary[status] = r.Count(status)
+	}
+
+	for _, result := range r.Results {
+		c := jsonComponent{
+			Name:     result.Component.Name(),
+			Title:    result.Component.Title(),
+			Status:   result.Status,
+			Reason:   result.Reason,
+			Findings: nonNil(result.Findings),
+			Checks:   []jsonCheck{},
+		}
+		if result.Err != nil {
+			c.Error = result.Err.Error()
+		}
+
+		for _, check := range result.Checks {
+			c.Checks = append(c.Checks, jsonCheck{
+				Check:    check.Check,
+				Status:   check.Status,
+				Reason:   check.Reason,
+				Findings: nonNil(check.Findings),
+			})
+		}
+
+		out.Components = append(out.Components, c)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// nonNil returns s, or an empty slice if s is nil, so that it is encoded as [] instead of null
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+
+	return s
+}
@@ -0,0 +1,110 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+func TestJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := JSON(&buf, mockReport()); err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	var actual jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &actual); err != nil {
+		t.Fatalf("expected valid JSON, got %v", err)
+	}
+
+	if actual.Cluster.VpcId != "vpc-1" {
+		t.Errorf("expected cluster vpcId vpc-1, got %s", actual.Cluster.VpcId)
+	}
+
+	if actual.Summary[mirrosa.StatusPassed] != 1 || actual.Summary[mirrosa.StatusFailed] != 1 {
+		t.Errorf("expected 1 passed and 1 failed component, got %v", actual.Summary)
+	}
+
+	if len(actual.Components) != 2 {
+		t.Fatalf("expected 2 components, got %d", len(actual.Components))
+	}
+
+	broken := actual.Components[1]
+	if broken.Name != "broken" || broken.Error != "api error" || len(broken.Checks) != 1 {
+		t.Errorf("unexpected broken component: %+v", broken)
+	}
+
+	if !reflect.DeepEqual(broken.Checks[0].Findings, mockReport().Results[1].Findings) {
+		t.Errorf("expected check findings %+v, got %+v", mockReport().Results[1].Findings, broken.Checks[0].Findings)
+	}
+
+	if healthy := actual.Components[0]; healthy.Findings == nil || healthy.Checks[0].Findings == nil {
+		t.Errorf("expected empty findings to be encoded as [], got %+v", healthy)
+	}
+
+	if !reflect.DeepEqual(actual.Resources, mockReport().Resources) {
+		t.Errorf("expected resources %+v, got %+v", mockReport().Resources, actual.Resources)
+	}
+}
+
+func TestJSON_EdgeCases(t *testing.T) {
+	tests := []struct {
+		name       string
+		report     mirrosa.Report
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name:       "empty report",
+			report:     mirrosa.Report{},
+			expected:   []string{`"components": []`, `"resources": []`, `"apiCalls": []`, `"passed": 0`, `"not-run": 0`},
+			unexpected: []string{"null"},
+		},
+		{
+			name: "component without checks",
+			report: mirrosa.Report{Results: []mirrosa.Result{
+				{Component: mockComponent{name: "unchecked"}, Status: mirrosa.StatusSkipped, Reason: "blocked by healthy"},
+			}},
+			expected:   []string{`"findings": []`, `"checks": []`, `"reason": "blocked by healthy"`, `"skipped": 1`},
+			unexpected: []string{"null", `"error": "`},
+		},
+		{
+			name: "error without findings",
+			report: mirrosa.Report{Results: []mirrosa.Result{
+				{Component: mockComponent{name: "errored"}, Status: mirrosa.StatusError, Err: errors.New(`access "denied"`)},
+			}},
+			expected:   []string{`"status": "error"`, `"error": "access \"denied\""`, `"findings": []`},
+			unexpected: []string{"null"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := JSON(&buf, test.report); err != nil {
+				t.Fatalf("expected no err, got %v", err)
+			}
+
+			if !json.Valid(buf.Bytes()) {
+				t.Fatalf("expected valid JSON, got %s", buf.String())
+			}
+
+			for _, s := range test.expected {
+				if !strings.Contains(buf.String(), s) {
+					t.Errorf("expected %q in report, got %s", s, buf.String())
+				}
+			}
+
+			for _, s := range test.unexpected {
+				if strings.Contains(buf.String(), s) {
+					t.Errorf("expected no %q in report, got %s", s, buf.String())
+				}
+			}
+		})
+	}
+}
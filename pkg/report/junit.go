@@ -0,0 +1,191 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite represents a single Component
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase represents a single Check of a Component
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitMessage is the failure, error, or skipped element of a test case
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// JUnit writes r to w as a JUnit XML report. Each Component is a test suite and each of its Checks is a test case,
+// so CI systems can track the history of every check. A Component without any Checks is a single test case.
+func JUnit(w io.Writer, r mirrosa.Report) error {
+	suites := junitTestSuites{Name: "mirrosa"}
+	if r.Cluster.Name != "" {
+		suites.Name = fmt.Sprintf("mirrosa %s", r.Cluster.Name)
+	}
+
+	for _, result := range r.Results {
+		suite := junitTestSuite{Name: result.Component.Title()}
+
+		checks := result.Checks
+		if len(checks) == 0 {
+			checks = []mirrosa.CheckResult{{
+				Check:    mirrosa.Check{Id: result.Component.Name(), Title: result.Component.Title()},
+				Status:   result.Status,
+				Reason:   result.Reason,
+				Findings: result.Findings,
+			}}
+		}
+
+		for _, check := range checks {
+			tc := junitCheck(result, check)
+			switch {
+			case tc.Failure != nil:
+				suite.Failures++
+			case tc.Error != nil:
+				suite.Errors++
+			case tc.Skipped != nil:
+				suite.Skipped++
+			}
+			suite.Tests++
+			suite.Cases = append(suite.Cases, tc)
+		}
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suites); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// junitCheck converts the outcome of a single Check into a test case. Checks that were not evaluated because their
+// Component could not finish validating are errors rather than skipped, so that they aren't mistaken for passing.
+func junitCheck(result mirrosa.Result, check mirrosa.CheckResult) junitTestCase {
+	tc := junitTestCase{
+		Name:      fmt.Sprintf("%s %s", check.Check.Id, check.Check.Title),
+		ClassName: result.Component.Name(),
+	}
+
+	switch check.Status {
+	case mirrosa.StatusWarning:
+		tc.SystemOut = junitFindings(check.Findings)
+	case mirrosa.StatusFailed:
+		// A failed check may also have warnings, which must not be mistaken for the reason it failed
+		failure := junitFailure(check.Findings)
+		tc.Failure = &junitMessage{
+			Message: failure.Message,
+			Type:    string(failure.Severity),
+			Body:    junitBody(result, check.Findings),
+		}
+	case mirrosa.StatusSkipped:
+		switch result.Status {
+		case mirrosa.StatusError, mirrosa.StatusTimedOut, mirrosa.StatusCancelled:
+			tc.Error = &junitMessage{
+				Message: result.Reason,
+				Type:    string(result.Status),
+				Body:    junitBody(result, check.Findings),
+			}
+		default:
+			// A Component can fail some checks and still be unable to evaluate the rest, e.g. because of an AWS API
+			// error, and those were not skipped on purpose
+			if result.Err != nil {
+				tc.Error = &junitMessage{
+					Message: check.Reason,
+					Type:    string(mirrosa.StatusError),
+					Body:    junitBody(result, check.Findings),
+				}
+				break
+			}
+			tc.Skipped = &junitMessage{Message: check.Reason}
+		}
+	case mirrosa.StatusError, mirrosa.StatusTimedOut, mirrosa.StatusCancelled:
+		tc.Error = &junitMessage{
+			Message: check.Reason,
+			Type:    string(check.Status),
+			Body:    junitBody(result, check.Findings),
+		}
+	case mirrosa.StatusNotRun:
+		tc.Skipped = &junitMessage{Message: check.Reason}
+	}
+
+	return tc
+}
+
+// junitFailure returns the first Finding with SeverityError, or the first Finding if there is none
+func junitFailure(findings []mirrosa.Finding) mirrosa.Finding {
+	for _, f := range findings {
+		if f.Severity == mirrosa.SeverityError {
+			return f
+		}
+	}
+
+	return findings[0]
+}
+
+// junitBody explains a failed or errored test case with its findings, the Component's error, and the Component's
+// Description so that the CI dashboard explains how the component should be configured
+func junitBody(result mirrosa.Result, findings []mirrosa.Finding) string {
+	var b strings.Builder
+	b.WriteString(junitFindings(findings))
+	if result.Err != nil {
+		fmt.Fprintf(&b, "could not finish validating: %s\n", result.Err)
+	}
+	fmt.Fprintf(&b, "\n%s\n", result.Component.Description())
+
+	return b.String()
+}
+
+// junitFindings renders findings one per line
+func junitFindings(findings []mirrosa.Finding) string {
+	var b strings.Builder
+	for _, f := range findings {
+		fmt.Fprintf(&b, "[%s] %s (resource: %s, expected: %s, actual: %s)\n", f.Severity, f, f.ResourceId, f.Expected, f.Actual)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,186 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+func TestJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := JUnit(&buf, mockReport()); err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	var actual junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &actual); err != nil {
+		t.Fatalf("expected valid XML, got %v", err)
+	}
+
+	if actual.Tests != 2 || actual.Failures != 1 || len(actual.Suites) != 2 {
+		t.Fatalf("expected 2 tests with 1 failure in 2 suites, got %+v", actual)
+	}
+
+	failure := actual.Suites[1].Cases[0].Failure
+	if failure == nil {
+		t.Fatal("expected MOCK-002 to fail")
+	}
+
+	for _, expected := range []string{"mock is false", "api error", "mock description"} {
+		if !strings.Contains(failure.Body, expected) {
+			t.Errorf("expected failure to contain %q, got %s", expected, failure.Body)
+		}
+	}
+}
+
+func TestJUnitCheck(t *testing.T) {
+	warning := mirrosa.Finding{CheckId: "MOCK-001", ResourceId: "vpc-1", Severity: mirrosa.SeverityWarning, Message: "mock is odd"}
+
+	tests := []struct {
+		name            string
+		result          mirrosa.Result
+		check           mirrosa.CheckResult
+		expectFailure   bool
+		expectError     bool
+		expectSkipped   bool
+		expectSystemOut string
+		expectMessage   string
+	}{
+		{
+			name:   "passed",
+			result: mirrosa.Result{Status: mirrosa.StatusPassed},
+			check:  mirrosa.CheckResult{Status: mirrosa.StatusPassed},
+		},
+		{
+			name:            "warnings are not failures",
+			result:          mirrosa.Result{Status: mirrosa.StatusWarning},
+			check:           mirrosa.CheckResult{Status: mirrosa.StatusWarning, Findings: []mirrosa.Finding{warning}},
+			expectSystemOut: "mock is odd",
+		},
+		{
+			name:          "failed",
+			result:        mirrosa.Result{Status: mirrosa.StatusFailed},
+			check:         mirrosa.CheckResult{Status: mirrosa.StatusFailed, Findings: []mirrosa.Finding{{Message: "mock is false"}}},
+			expectFailure: true,
+		},
+		{
+			name:   "failed with a warning first",
+			result: mirrosa.Result{Status: mirrosa.StatusFailed},
+			check: mirrosa.CheckResult{Status: mirrosa.StatusFailed, Findings: []mirrosa.Finding{
+				warning,
+				{CheckId: "MOCK-001", ResourceId: "vpc-2", Severity: mirrosa.SeverityError, Message: "mock is false"},
+			}},
+			expectFailure: true,
+			expectMessage: "mock is false",
+		},
+		{
+			name:          "not evaluated after other checks failed",
+			result:        mirrosa.Result{Status: mirrosa.StatusFailed, Err: errors.New("api error")},
+			check:         mirrosa.CheckResult{Status: mirrosa.StatusSkipped, Reason: "could not be evaluated: api error"},
+			expectError:   true,
+			expectMessage: "could not be evaluated: api error",
+		},
+		{
+			name:          "blocked by a dependency",
+			result:        mirrosa.Result{Status: mirrosa.StatusSkipped, Reason: "blocked by healthy"},
+			check:         mirrosa.CheckResult{Status: mirrosa.StatusSkipped, Reason: "blocked by healthy"},
+			expectSkipped: true,
+		},
+		{
+			name:        "not evaluated because of an error",
+			result:      mirrosa.Result{Status: mirrosa.StatusError, Err: errors.New("api error")},
+			check:       mirrosa.CheckResult{Status: mirrosa.StatusSkipped},
+			expectError: true,
+		},
+		{
+			name:        "not evaluated because of a timeout",
+			result:      mirrosa.Result{Status: mirrosa.StatusTimedOut},
+			check:       mirrosa.CheckResult{Status: mirrosa.StatusSkipped},
+			expectError: true,
+		},
+		{
+			name:        "timed out",
+			result:      mirrosa.Result{Status: mirrosa.StatusTimedOut},
+			check:       mirrosa.CheckResult{Status: mirrosa.StatusTimedOut},
+			expectError: true,
+		},
+		{
+			name:          "not run",
+			result:        mirrosa.Result{Status: mirrosa.StatusNotRun},
+			check:         mirrosa.CheckResult{Status: mirrosa.StatusNotRun},
+			expectSkipped: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.result.Component = mockComponent{name: "mock"}
+			tc := junitCheck(test.result, test.check)
+
+			if (tc.Failure != nil) != test.expectFailure {
+				t.Errorf("failure: expected %t, got %+v", test.expectFailure, tc.Failure)
+			}
+
+			if (tc.Error != nil) != test.expectError {
+				t.Errorf("error: expected %t, got %+v", test.expectError, tc.Error)
+			}
+
+			if (tc.Skipped != nil) != test.expectSkipped {
+				t.Errorf("skipped: expected %t, got %+v", test.expectSkipped, tc.Skipped)
+			}
+
+			if test.expectMessage != "" {
+				var message string
+				switch {
+				case tc.Failure != nil:
+					message = tc.Failure.Message
+				case tc.Error != nil:
+					message = tc.Error.Message
+				}
+				if message != test.expectMessage {
+					t.Errorf("expected message %q, got %q", test.expectMessage, message)
+				}
+			}
+
+			if !strings.Contains(tc.SystemOut, test.expectSystemOut) || (test.expectSystemOut == "" && tc.SystemOut != "") {
+				t.Errorf("expected system-out to contain %q, got %q", test.expectSystemOut, tc.SystemOut)
+			}
+		})
+	}
+}
+
+func TestJUnit_ComponentWithoutChecks(t *testing.T) {
+	r := mirrosa.Report{
+		Cluster: mirrosa.ClusterInfo{Name: "mock <&>"},
+		Results: []mirrosa.Result{
+			{Component: mockComponent{name: "unchecked"}, Status: mirrosa.StatusFailed, Findings: []mirrosa.Finding{{Message: "mock <is> false"}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := JUnit(&buf, r); err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	var actual junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &actual); err != nil {
+		t.Fatalf("expected valid XML, got %v", err)
+	}
+
+	if actual.Name != "mirrosa mock <&>" {
+		t.Errorf("expected the cluster's name to be escaped, got %q", actual.Name)
+	}
+
+	if len(actual.Suites) != 1 || len(actual.Suites[0].Cases) != 1 {
+		t.Fatalf("expected a single test case for the component, got %+v", actual.Suites)
+	}
+
+	tc := actual.Suites[0].Cases[0]
+	if tc.Name != "unchecked UNCHECKED" || tc.Failure == nil || tc.Failure.Message != "mock <is> false" {
+		t.Errorf("expected the component's findings to fail its test case, got %+v", tc)
+	}
+}
@@ -0,0 +1,99 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+// Markdown writes r to w as a Markdown document suitable for pasting into a support ticket. It contains a summary
+// table, followed by a section for each component with its Description, checks, and findings.
+func Markdown(w io.Writer, r mirrosa.Report) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# mirrosa report for %s\n\n", markdownText(r.Cluster.Name))
+	b.WriteString("| Cluster | Infra Name | AWS Account | Region | VPC |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n\n",
+		markdownCell(r.Cluster.Name),
+		markdownCell(r.Cluster.InfraName),
+		markdownCell(r.Cluster.AccountId),
+		markdownCell(r.Cluster.Region),
+		markdownCode(r.Cluster.VpcId),
+	)
+
+	b.WriteString("## Summary\n\n")
+	b.WriteString("| Component | Result | Reason |\n")
+	b.WriteString("| --- | --- | --- |\n")
+	for _, result := range r.Results {
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", markdownCell(result.Component.Title()), formatStatus(result.Status, ""), markdownCell(result.Reason))
+	}
+
+	for _, result := range r.Results {
+		fmt.Fprintf(&b, "\n## %s: %s\n\n", markdownText(result.Component.Title()), formatStatus(result.Status, result.Reason))
+		fmt.Fprintf(&b, "%s\n", result.Component.Description())
+
+		if len(result.Checks) > 0 {
+			b.WriteString("\n| Check | Title | Result |\n")
+			b.WriteString("| --- | --- | --- |\n")
+			for _, check := range result.Checks {
+				fmt.Fprintf(&b, "| %s | %s | %s |\n", check.Check.Id, markdownCell(check.Check.Title), markdownCell(formatStatus(check.Status, check.Reason)))
+			}
+		}
+
+		if len(result.Findings) > 0 {
+			b.WriteString("\n### Findings\n\n")
+			b.WriteString("| Check | Severity | Resource | Expected | Actual | Message |\n")
+			b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+			for _, f := range result.Findings {
+				fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+					f.CheckId,
+					f.Severity,
+					markdownCode(f.ResourceId),
+					markdownCell(f.Expected),
+					markdownCell(f.Actual),
+					markdownCell(f.Message),
+				)
+			}
+		}
+
+		if result.Err != nil {
+			fmt.Fprintf(&b, "\nCould not finish validating: %s\n", markdownText(result.Err.Error()))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownEscaper escapes characters that Markdown would otherwise interpret inline
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"|", `\|`,
+	"<", "&lt;",
+	">", "&gt;",
+)
+
+// markdownText escapes s so that it is rendered literally
+func markdownText(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// markdownCell escapes s so that it fits in a single table cell
+func markdownCell(s string) string {
+	return strings.ReplaceAll(markdownText(s), "\n", "<br>")
+}
+
+// markdownCode renders an identifier as inline code so it can be copied as-is
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return "`" + strings.ReplaceAll(s, "`", "") + "`"
+}
@@ -0,0 +1,94 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		name       string
+		report     func() mirrosa.Report
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name:     "mock report",
+			report:   mockReport,
+			expected: []string{"# mirrosa report for mock", "| HEALTHY | PASSED |", "mock description", "`vpc-1`", "Could not finish validating: api error"},
+		},
+		{
+			name: "html is escaped",
+			report: func() mirrosa.Report {
+				r := mockReport()
+				r.Results[1].Findings[0].Actual = "<script>alert(1)</script>"
+				return r
+			},
+			expected:   []string{"&lt;script&gt;"},
+			unexpected: []string{"<script>"},
+		},
+		{
+			name: "table cells stay on one row",
+			report: func() mirrosa.Report {
+				r := mockReport()
+				r.Results[1].Findings[0].Message = "mock | is\nfalse"
+				return r
+			},
+			expected: []string{`mock \| is<br>false |`},
+		},
+		{
+			name: "inline markdown is escaped",
+			report: func() mirrosa.Report {
+				r := mockReport()
+				r.Cluster.Name = "*mock_cluster*"
+				r.Results[0].Reason = "`not code`"
+				return r
+			},
+			expected:   []string{`# mirrosa report for \*mock\_cluster\*`, "| HEALTHY | PASSED | \\`not code\\` |"},
+			unexpected: []string{"*mock_cluster*"},
+		},
+		{
+			name: "resource ids are copyable code",
+			report: func() mirrosa.Report {
+				r := mockReport()
+				r.Results[1].Findings[0].ResourceId = "vpc`-1"
+				return r
+			},
+			expected: []string{"| MOCK-002 | error | `vpc-1` |"},
+		},
+		{
+			name: "skipped reason",
+			report: func() mirrosa.Report {
+				r := mockReport()
+				r.Results[1] = mirrosa.Result{Component: mockComponent{name: "broken"}, Status: mirrosa.StatusSkipped, Reason: "blocked by healthy"}
+				return r
+			},
+			expected:   []string{"| BROKEN | SKIPPED | blocked by healthy |", "## BROKEN: SKIPPED: blocked by healthy"},
+			unexpected: []string{"### Findings", "Could not finish validating"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Markdown(&buf, test.report()); err != nil {
+				t.Fatalf("expected no err, got %v", err)
+			}
+
+			for _, s := range test.expected {
+				if !strings.Contains(buf.String(), s) {
+					t.Errorf("expected report to contain %q, got %s", s, buf.String())
+				}
+			}
+
+			for _, s := range test.unexpected {
+				if strings.Contains(buf.String(), s) {
+					t.Errorf("expected report to not contain %q, got %s", s, buf.String())
+				}
+			}
+		})
+	}
+}
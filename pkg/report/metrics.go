@@ -0,0 +1,132 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+// MetricsContentType is the Content-Type of the Prometheus text exposition format written by Metrics
+const MetricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// metric is a single sample with its labels, in the order they are written
+type metric struct {
+	labels []string
+	value  float64
+}
+
+// metricFamily is every sample of a metric with the same name
+type metricFamily struct {
+	name    string
+	help    string
+	kind    string
+	metrics []metric
+}
+
+// Metrics writes r to w in the Prometheus text exposition format, which is also understood by OpenMetrics parsers and
+// node_exporter's textfile collector. Every sample is labelled with the cluster's name and infra ID so that results
+// from several clusters can be aggregated.
+func Metrics(w io.Writer, r mirrosa.Report) error {
+	cluster := []string{"cluster", r.Cluster.Name, "infra_id", r.Cluster.InfraName}
+	labels := func(kv ...string) []string {
+		return append(append([]string{}, cluster...), kv...)
+	}
+
+	var (
+		componentPassed = metricFamily{name: "mirrosa_component_passed", kind: "gauge",
+			help: "Whether the component passed validation, possibly with warnings."}
+		componentStatus = metricFamily{name: "mirrosa_component_status", kind: "gauge",
+			help: "The outcome of validating the component, 1 for its current status and 0 for every other status."}
+		checkPassed = metricFamily{name: "mirrosa_check_passed", kind: "gauge",
+			help: "Whether the check passed, possibly with warnings."}
+		findings = metricFamily{name: "mirrosa_check_findings", kind: "gauge",
+			help: "Number of findings of the check."}
+		runDuration = metricFamily{name: "mirrosa_run_duration_seconds", kind: "gauge",
+			help: "Duration of validating every component."}
+		runTimestamp = metricFamily{name: "mirrosa_run_timestamp_seconds", kind: "gauge",
+			help: "Unix time at which validation started."}
+		apiCalls = metricFamily{name: "mirrosa_aws_api_calls_total", kind: "counter",
+			help: "Number of AWS API calls sent to AWS."}
+		apiCached = metricFamily{name: "mirrosa_aws_api_cached_calls_total", kind: "counter",
+			help: "Number of AWS API calls answered from mirrosa's cache."}
+		apiRetries = metricFamily{name: "mirrosa_aws_api_retries_total", kind: "counter",
+			help: "Number of AWS API call attempts that were retried."}
+		apiThrottles = metricFamily{name: "mirrosa_aws_api_throttles_total", kind: "counter",
+			help: "Number of AWS API call attempts that were throttled."}
+	)
+
+	for _, result := range r.Results {
+		name := result.Component.Name()
+		componentPassed.add(boolValue(result.Ok()), labels("component", name)...)
+		for _, status := range mirrosa.Statuses {
+			componentStatus.add(boolValue(result.Status == status), labels("component", name, "status", string(status))...)
+		}
+
+		for _, check := range result.Checks {
+			checkLabels := labels("component", name, "check", check.Check.Id)
+			checkPassed.add(boolValue(check.Status == mirrosa.StatusPassed || check.Status == mirrosa.StatusWarning), checkLabels...)
+			findings.add(float64(len(check.Findings)), checkLabels...)
+		}
+	}
+
+	runDuration.add(r.Duration.Seconds(), cluster...)
+	if !r.Started.IsZero() {
+		runTimestamp.add(float64(r.Started.UnixMilli())/1000, cluster...)
+	}
+
+	for _, s := range r.ApiCalls {
+		apiLabels := labels("service", s.Service, "operation", s.Operation)
+		apiCalls.add(float64(s.Calls), apiLabels...)
+		apiCached.add(float64(s.Cached), apiLabels...)
+		apiRetries.add(float64(s.Retries), apiLabels...)
+		apiThrottles.add(float64(s.Throttles), apiLabels...)
+	}
+
+	var b strings.Builder
+	for _, family := range []metricFamily{
+		componentPassed, componentStatus, checkPassed, findings, runDuration, runTimestamp,
+		apiCalls, apiCached, apiRetries, apiThrottles,
+	} {
+		family.write(&b)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// add records a sample with labels given as alternating names and values
+func (f *metricFamily) add(value float64, labels ...string) {
+	f.metrics = append(f.metrics, metric{labels: labels, value: value})
+}
+
+// write writes the family's metadata and samples, or nothing if it has no samples
+func (f *metricFamily) write(b *strings.Builder) {
+	if len(f.metrics) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "# HELP %s %s\n", f.name, f.help)
+	fmt.Fprintf(b, "# TYPE %s %s\n", f.name, f.kind)
+	for _, m := range f.metrics {
+		var labels []string
+		for i := 0; i+1 < len(m.labels); i += 2 {
+			labels = append(labels, fmt.Sprintf(`%s="%s"`, m.labels[i], metricLabelEscaper.Replace(m.labels[i+1])))
+		}
+		fmt.Fprintf(b, "%s{%s} %s\n", f.name, strings.Join(labels, ","), strconv.FormatFloat(m.value, 'g', -1, 64))
+	}
+}
+
+// metricLabelEscaper escapes label values as required by the Prometheus text exposition format
+var metricLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// boolValue converts b to a gauge value
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,102 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+func TestMetrics(t *testing.T) {
+	tests := []struct {
+		name       string
+		report     func() mirrosa.Report
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name: "mock report",
+			report: func() mirrosa.Report {
+				r := mockReport()
+				r.Duration = 1500 * time.Millisecond
+				return r
+			},
+			expected: []string{
+				"# TYPE mirrosa_component_passed gauge\n",
+				`mirrosa_component_passed{cluster="mock",infra_id="mock-abcde",component="healthy"} 1`,
+				`mirrosa_component_passed{cluster="mock",infra_id="mock-abcde",component="broken"} 0`,
+				`mirrosa_component_status{cluster="mock",infra_id="mock-abcde",component="broken",status="failed"} 1`,
+				`mirrosa_component_status{cluster="mock",infra_id="mock-abcde",component="broken",status="passed"} 0`,
+				`mirrosa_check_passed{cluster="mock",infra_id="mock-abcde",component="broken",check="MOCK-002"} 0`,
+				`mirrosa_check_findings{cluster="mock",infra_id="mock-abcde",component="broken",check="MOCK-002"} 1`,
+				`mirrosa_run_duration_seconds{cluster="mock",infra_id="mock-abcde"} 1.5`,
+				"# TYPE mirrosa_aws_api_calls_total counter\n",
+				`mirrosa_aws_api_calls_total{cluster="mock",infra_id="mock-abcde",service="EC2",operation="DescribeVpcs"} 1`,
+			},
+			unexpected: []string{"mirrosa_run_timestamp_seconds"},
+		},
+		{
+			name: "label values are escaped",
+			report: func() mirrosa.Report {
+				r := mockReport()
+				r.Cluster.Name = "mock \"quoted\"\nback\\slash"
+				return r
+			},
+			expected: []string{`mirrosa_component_passed{cluster="mock \"quoted\"\nback\\slash",infra_id="mock-abcde",component="healthy"} 1`},
+		},
+		{
+			name: "warnings pass",
+			report: func() mirrosa.Report {
+				r := mockReport()
+				r.Results[1].Status = mirrosa.StatusWarning
+				r.Results[1].Checks[0].Status = mirrosa.StatusWarning
+				return r
+			},
+			expected: []string{
+				`mirrosa_component_passed{cluster="mock",infra_id="mock-abcde",component="broken"} 1`,
+				`mirrosa_component_status{cluster="mock",infra_id="mock-abcde",component="broken",status="warning"} 1`,
+				`mirrosa_check_passed{cluster="mock",infra_id="mock-abcde",component="broken",check="MOCK-002"} 1`,
+			},
+		},
+		{
+			name: "start time",
+			report: func() mirrosa.Report {
+				r := mockReport()
+				r.Started = time.UnixMilli(1700000000250)
+				return r
+			},
+			expected: []string{`mirrosa_run_timestamp_seconds{cluster="mock",infra_id="mock-abcde"} 1.70000000025e+09`},
+		},
+		{
+			name: "families without samples are omitted",
+			report: func() mirrosa.Report {
+				return mirrosa.Report{Cluster: mirrosa.ClusterInfo{Name: "mock"}}
+			},
+			expected:   []string{"# TYPE mirrosa_run_duration_seconds gauge\n", `mirrosa_run_duration_seconds{cluster="mock",infra_id=""} 0`},
+			unexpected: []string{"mirrosa_component_passed", "mirrosa_aws_api_calls_total"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Metrics(&buf, test.report()); err != nil {
+				t.Fatalf("expected no err, got %v", err)
+			}
+
+			for _, s := range test.expected {
+				if !strings.Contains(buf.String(), s) {
+					t.Errorf("expected metrics to contain %q, got %s", s, buf.String())
+				}
+			}
+
+			for _, s := range test.unexpected {
+				if strings.Contains(buf.String(), s) {
+					t.Errorf("expected metrics to not contain %q, got %s", s, buf.String())
+				}
+			}
+		})
+	}
+}
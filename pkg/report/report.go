@@ -0,0 +1,46 @@
+// Package report renders a mirrosa.Report in the formats supported by mirrosa's --output flag
+package report
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+// Renderer writes a mirrosa.Report to w in a specific format
+type Renderer func(w io.Writer, r mirrosa.Report) error
+
+// renderers maps each supported output format to its Renderer
+var renderers = map[string]Renderer{
+	"text":       Text,
+	"html":       HTML,
+	"markdown":   Markdown,
+	"prometheus": Metrics,
+	"json":       JSON,
+	"junit":      JUnit,
+	"sarif":      SARIF,
+}
+
+// Formats returns the name of every supported output format, sorted
+func Formats() []string {
+	formats := make([]string, 0, len(renderers))
+	for format := range renderers {
+		formats = append(formats, format)
+	}
+	slices.Sort(formats)
+
+	return formats
+}
+
+// Render writes r to w in the given format
+func Render(w io.Writer, format string, r mirrosa.Report) error {
+	render, ok := renderers[format]
+	if !ok {
+		return fmt.Errorf("unsupported output format %q, must be one of: %s", format, strings.Join(Formats(), ", "))
+	}
+
+	return render(w, r)
+}
@@ -0,0 +1,103 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+// mockComponent is a mirrosa.Component that is only used for rendering
+type mockComponent struct {
+	mirrosa.Component
+	name string
+	deps []string
+}
+
+func (m mockComponent) Name() string           { return m.name }
+func (m mockComponent) Title() string          { return strings.ToUpper(m.name) }
+func (m mockComponent) Description() string    { return "mock description" }
+func (m mockComponent) Dependencies() []string { return m.deps }
+
+// mockReport returns a Report with a passed and a failed component
+func mockReport() mirrosa.Report {
+	finding := mirrosa.Finding{
+		CheckId:    "MOCK-002",
+		ResourceId: "vpc-1",
+		Expected:   "true",
+		Actual:     "false",
+		Severity:   mirrosa.SeverityError,
+		Message:    "mock is false",
+	}
+
+	return mirrosa.Report{
+		Cluster: mirrosa.ClusterInfo{
+			Name:       "mock",
+			InfraName:  "mock-abcde",
+			BaseDomain: "mock.example.com",
+			VpcId:      "vpc-1",
+			AccountId:  "123456789012",
+			Region:     "us-east-1",
+		},
+		Results: []mirrosa.Result{
+			{
+				Component: mockComponent{name: "healthy"},
+				Status:    mirrosa.StatusPassed,
+				Checks: []mirrosa.CheckResult{
+					{Check: mirrosa.Check{Id: "MOCK-001", Title: "healthy"}, Status: mirrosa.StatusPassed},
+				},
+			},
+			{
+				Component: mockComponent{name: "broken", deps: []string{"healthy"}},
+				Status:    mirrosa.StatusFailed,
+				Findings:  []mirrosa.Finding{finding},
+				Err:       errors.New("api error"),
+				Checks: []mirrosa.CheckResult{
+					{Check: mirrosa.Check{Id: "MOCK-002", Title: "broken"}, Status: mirrosa.StatusFailed, Findings: []mirrosa.Finding{finding}},
+				},
+			},
+		},
+		Resources: []mirrosa.Resource{{Type: mirrosa.ResourceTypeVpc, Id: "vpc-1", Name: "mock-abcde-vpc", Component: "broken"}},
+		ApiCalls:  []mirrosa.ApiCallStats{{Service: "EC2", Operation: "DescribeVpcs", Calls: 1}},
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		expectErr bool
+	}{
+		{name: "text", format: "text"},
+		{name: "json", format: "json"},
+		{name: "junit", format: "junit"},
+		{name: "sarif", format: "sarif"},
+		{name: "markdown", format: "markdown"},
+		{name: "html", format: "html"},
+		{name: "prometheus", format: "prometheus"},
+		{name: "unsupported", format: "yaml", expectErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := Render(&buf, test.format, mockReport())
+			if err != nil {
+				if !test.expectErr {
+					t.Errorf("expected no err, got %v", err)
+				}
+				return
+			}
+
+			if test.expectErr {
+				t.Error("expected err, got nil")
+			}
+
+			if !strings.Contains(buf.String(), "MOCK-002") {
+				t.Errorf("expected report to contain MOCK-002, got %s", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	Results     []sarifResult     `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationUri string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	Id                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	FullDescription      sarifMessage       `json:"fullDescription"`
+	Help                 sarifMessage       `json:"help"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications"`
+}
+
+type sarifNotification struct {
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifResult struct {
+	RuleId              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	Uri string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// SARIF writes r to w as a SARIF 2.1.0 log. Every Check is a rule, named after its Component with the Component's
+// Description as help text, and every Finding is a result located at the ARN of the offending AWS resource. Findings
+// about resources without an ARN, e.g. because they weren't found, are located at the cluster's VPC instead.
+func SARIF(w io.Writer, r mirrosa.Report) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "mirrosa",
+			InformationUri: "https://github.com/mjlshen/mirrosa",
+			Rules:          []sarifRule{},
+		}},
+		Invocations: []sarifInvocation{{ExecutionSuccessful: true, ToolExecutionNotifications: []sarifNotification{}}},
+		Results:     []sarifResult{},
+	}
+
+	ruleIndex := map[string]int{}
+	for _, result := range r.Results {
+		for _, check := range result.Checks {
+			if _, ok := ruleIndex[check.Check.Id]; ok {
+				continue
+			}
+
+			ruleIndex[check.Check.Id] = len(run.Tool.Driver.Rules)
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				Id:                   check.Check.Id,
+				Name:                 result.Component.Title(),
+				ShortDescription:     sarifMessage{Text: check.Check.Title},
+				FullDescription:      sarifMessage{Text: result.Component.Description()},
+				Help:                 sarifMessage{Text: result.Component.Description()},
+				DefaultConfiguration: sarifConfiguration{Level: sarifLevel(check.Check.Severity)},
+			})
+		}
+
+		for _, check := range result.Checks {
+			for _, finding := range check.Findings {
+				run.Results = append(run.Results, sarifResult{
+					RuleId:    finding.CheckId,
+					RuleIndex: ruleIndex[finding.CheckId],
+					Level:     sarifLevel(finding.Severity),
+					Message:   sarifMessage{Text: fmt.Sprintf("%s (expected: %s, actual: %s)", finding.Message, finding.Expected, finding.Actual)},
+					Locations: []sarifLocation{sarifResourceLocation(r.Cluster, finding.ResourceId)},
+					PartialFingerprints: map[string]string{
+						"mirrosaFinding/v1": fmt.Sprintf("%s:%s", finding.CheckId, finding.ResourceId),
+					},
+				})
+			}
+		}
+
+		// Components that couldn't be fully validated are reported as notifications rather than results, since they
+		// say nothing about whether the resources are misconfigured
+		if result.Err != nil || (!result.Ok() && len(result.Findings) == 0) {
+			message := fmt.Sprintf("%s %s", result.Component.Title(), result.Status)
+			if result.Reason != "" {
+				message = fmt.Sprintf("%s: %s", message, result.Reason)
+			}
+			if result.Err != nil {
+				message = fmt.Sprintf("%s: %s", message, result.Err)
+			}
+
+			level := "note"
+			if result.Status != mirrosa.StatusSkipped && result.Status != mirrosa.StatusNotRun {
+				level = "error"
+				run.Invocations[0].ExecutionSuccessful = false
+			}
+
+			run.Invocations[0].ToolExecutionNotifications = append(run.Invocations[0].ToolExecutionNotifications, sarifNotification{
+				Level:   level,
+				Message: sarifMessage{Text: message},
+			})
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs:    []sarifRun{run},
+	})
+}
+
+// sarifLevel maps the Severity of a Finding or Check to a SARIF level
+func sarifLevel(severity mirrosa.Severity) string {
+	if severity == mirrosa.SeverityWarning {
+		return "warning"
+	}
+
+	return "error"
+}
+
+// sarifResourceLocation locates a Finding at the ARN of the AWS resource it is about, falling back to the cluster's VPC
+func sarifResourceLocation(cluster mirrosa.ClusterInfo, resourceId string) sarifLocation {
+	location := sarifLocation{
+		LogicalLocations: []sarifLogicalLocation{{
+			Name:               resourceId,
+			FullyQualifiedName: resourceId,
+			Kind:               "resource",
+		}},
+	}
+
+	uri := cluster.ResourceArn(resourceId)
+	if uri == "" {
+		uri = cluster.ResourceArn(cluster.VpcId)
+	} else {
+		location.LogicalLocations[0].FullyQualifiedName = uri
+	}
+
+	if uri != "" {
+		location.PhysicalLocation = &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{Uri: uri}}
+	}
+
+	return location
+}
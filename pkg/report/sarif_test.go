@@ -0,0 +1,225 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+func TestSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := SARIF(&buf, mockReport()); err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	var actual sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &actual); err != nil {
+		t.Fatalf("expected valid JSON, got %v", err)
+	}
+
+	if actual.Version != "2.1.0" || len(actual.Runs) != 1 {
+		t.Fatalf("expected a single SARIF 2.1.0 run, got %+v", actual)
+	}
+
+	run := actual.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 {
+		t.Errorf("expected a rule for each check, got %+v", run.Tool.Driver.Rules)
+	}
+
+	if len(run.Results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(run.Results))
+	}
+
+	result := run.Results[0]
+	if rule := run.Tool.Driver.Rules[result.RuleIndex]; rule.Id != "MOCK-002" || rule.Help.Text != "mock description" {
+		t.Errorf("expected result to reference the MOCK-002 rule, got %+v", rule)
+	}
+
+	expectedUri := "arn:aws:ec2:us-east-1:123456789012:vpc/vpc-1"
+	if uri := result.Locations[0].PhysicalLocation.ArtifactLocation.Uri; uri != expectedUri {
+		t.Errorf("expected location %s, got %s", expectedUri, uri)
+	}
+
+	if run.Invocations[0].ExecutionSuccessful {
+		t.Error("expected the component that could not finish validating to be reported")
+	}
+}
+
+func TestSARIF_RuleLevel(t *testing.T) {
+	r := mirrosa.Report{Results: []mirrosa.Result{{
+		Component: mockComponent{name: "mock"},
+		Status:    mirrosa.StatusPassed,
+		Checks: []mirrosa.CheckResult{
+			{Check: mirrosa.Check{Id: "MOCK-001"}, Status: mirrosa.StatusPassed},
+			{Check: mirrosa.Check{Id: "MOCK-002", Severity: mirrosa.SeverityWarning}, Status: mirrosa.StatusPassed},
+		},
+	}}}
+
+	var buf bytes.Buffer
+	if err := SARIF(&buf, r); err != nil {
+		t.Fatalf("expected no err, got %v", err)
+	}
+
+	var actual sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &actual); err != nil {
+		t.Fatalf("expected valid JSON, got %v", err)
+	}
+
+	expected := map[string]string{"MOCK-001": "error", "MOCK-002": "warning"}
+	for _, rule := range actual.Runs[0].Tool.Driver.Rules {
+		if rule.DefaultConfiguration.Level != expected[rule.Id] {
+			t.Errorf("expected rule %s to have level %s, got %s", rule.Id, expected[rule.Id], rule.DefaultConfiguration.Level)
+		}
+	}
+}
+
+func TestSARIF_Notifications(t *testing.T) {
+	tests := []struct {
+		name              string
+		result            mirrosa.Result
+		expectLevel       string
+		expectSuccessful  bool
+		expectResultLevel string
+	}{
+		{
+			name:             "passed",
+			result:           mirrosa.Result{Status: mirrosa.StatusPassed},
+			expectSuccessful: true,
+		},
+		{
+			name: "warning",
+			result: mirrosa.Result{
+				Status:   mirrosa.StatusWarning,
+				Findings: []mirrosa.Finding{{CheckId: "MOCK-001", Severity: mirrosa.SeverityWarning}},
+				Checks: []mirrosa.CheckResult{{
+					Check:    mirrosa.Check{Id: "MOCK-001"},
+					Status:   mirrosa.StatusWarning,
+					Findings: []mirrosa.Finding{{CheckId: "MOCK-001", Severity: mirrosa.SeverityWarning}},
+				}},
+			},
+			expectSuccessful:  true,
+			expectResultLevel: "warning",
+		},
+		{
+			name:             "blocked by a dependency",
+			result:           mirrosa.Result{Status: mirrosa.StatusSkipped, Reason: "blocked by healthy"},
+			expectLevel:      "note",
+			expectSuccessful: true,
+		},
+		{
+			name:             "not run",
+			result:           mirrosa.Result{Status: mirrosa.StatusNotRun},
+			expectLevel:      "note",
+			expectSuccessful: true,
+		},
+		{
+			name:        "error",
+			result:      mirrosa.Result{Status: mirrosa.StatusError, Err: errors.New("api error")},
+			expectLevel: "error",
+		},
+		{
+			name:        "timed out",
+			result:      mirrosa.Result{Status: mirrosa.StatusTimedOut},
+			expectLevel: "error",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.result.Component = mockComponent{name: "mock"}
+
+			var buf bytes.Buffer
+			if err := SARIF(&buf, mirrosa.Report{Results: []mirrosa.Result{test.result}}); err != nil {
+				t.Fatalf("expected no err, got %v", err)
+			}
+
+			var actual sarifLog
+			if err := json.Unmarshal(buf.Bytes(), &actual); err != nil {
+				t.Fatalf("expected valid JSON, got %v", err)
+			}
+
+			invocation := actual.Runs[0].Invocations[0]
+			if invocation.ExecutionSuccessful != test.expectSuccessful {
+				t.Errorf("expected executionSuccessful %t, got %t", test.expectSuccessful, invocation.ExecutionSuccessful)
+			}
+
+			var level string
+			if len(invocation.ToolExecutionNotifications) > 0 {
+				level = invocation.ToolExecutionNotifications[0].Level
+			}
+			if level != test.expectLevel {
+				t.Errorf("expected notification level %q, got %+v", test.expectLevel, invocation.ToolExecutionNotifications)
+			}
+
+			var resultLevel string
+			if len(actual.Runs[0].Results) > 0 {
+				resultLevel = actual.Runs[0].Results[0].Level
+			}
+			if resultLevel != test.expectResultLevel {
+				t.Errorf("expected result level %q, got %+v", test.expectResultLevel, actual.Runs[0].Results)
+			}
+		})
+	}
+}
+
+func TestSarifResourceLocation(t *testing.T) {
+	cluster := mirrosa.ClusterInfo{VpcId: "vpc-1", AccountId: "123456789012", Region: "us-east-1"}
+
+	tests := []struct {
+		name       string
+		cluster    mirrosa.ClusterInfo
+		resourceId string
+		expected   string
+	}{
+		{
+			name:       "resource id",
+			cluster:    cluster,
+			resourceId: "sg-1",
+			expected:   "arn:aws:ec2:us-east-1:123456789012:security-group/sg-1",
+		},
+		{
+			name:       "arn",
+			cluster:    cluster,
+			resourceId: "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/mock-int/1",
+			expected:   "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/mock-int/1",
+		},
+		{
+			name:       "hosted zone",
+			cluster:    cluster,
+			resourceId: "/hostedzone/Z1",
+			expected:   "arn:aws:route53:::hostedzone/Z1",
+		},
+		{
+			name:       "name of a missing resource falls back to the vpc",
+			cluster:    cluster,
+			resourceId: "mock-abcde-master-sg",
+			expected:   "arn:aws:ec2:us-east-1:123456789012:vpc/vpc-1",
+		},
+		{
+			name:       "unknown account",
+			cluster:    mirrosa.ClusterInfo{VpcId: "vpc-1"},
+			resourceId: "sg-1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			location := sarifResourceLocation(test.cluster, test.resourceId)
+			var actual string
+			if location.PhysicalLocation != nil {
+				actual = location.PhysicalLocation.ArtifactLocation.Uri
+			}
+
+			if actual != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, actual)
+			}
+
+			if location.LogicalLocations[0].Name != test.resourceId {
+				t.Errorf("expected logical location %s, got %s", test.resourceId, location.LogicalLocations[0].Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+)
+
+// Text writes the result of every validated component and its checks to w as a human-readable table, followed by
+// any findings and a summary of the AWS API calls that were made
+func Text(w io.Writer, r mirrosa.Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tTITLE\tRESULT")
+	for _, result := range r.Results {
+		fmt.Fprintf(tw, "%s\t\t%s\n", result.Component.Title(), formatStatus(result.Status, result.Reason))
+		for _, check := range result.Checks {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", check.Check.Id, check.Check.Title, formatStatus(check.Status, check.Reason))
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	var summary []string
+	for _, status := range mirrosa.Statuses {
+		if n := r.Count(status); n > 0 || status == mirrosa.StatusPassed {
+			summary = append(summary, fmt.Sprintf("%d %s", n, status))
+		}
+	}
+	fmt.Fprintf(w, "\nSummary: %s\n", strings.Join(summary, ", "))
+
+	for _, result := range r.Results {
+		if len(result.Findings) == 0 && result.Err == nil {
+			continue
+		}
+
+		fmt.Fprintf(w, "\n%s:\n", result.Component.Title())
+		for _, finding := range result.Findings {
+			fmt.Fprintf(w, "  - [%s] %s\n", finding.Severity, finding)
+			fmt.Fprintf(w, "      resource: %s, expected: %s, actual: %s\n", finding.ResourceId, finding.Expected, finding.Actual)
+		}
+		if result.Err != nil {
+			fmt.Fprintf(w, "  - could not finish validating: %s\n", result.Err)
+		}
+	}
+
+	if len(r.ApiCalls) > 0 {
+		fmt.Fprintln(w)
+		tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "AWS API\tCALLS\tCACHED\tRETRIES\tTHROTTLES")
+		for _, s := range r.ApiCalls {
+			fmt.Fprintf(tw, "%s:%s\t%d\t%d\t%d\t%d\n", s.Service, s.Operation, s.Calls, s.Cached, s.Retries, s.Throttles)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatStatus renders a status, and the reason for it if there is one, for Text
+func formatStatus(status mirrosa.Status, reason string) string {
+	if reason == "" {
+		return strings.ToUpper(string(status))
+	}
+
+	return fmt.Sprintf("%s: %s", strings.ToUpper(string(status)), reason)
+}
@@ -80,15 +80,12 @@ func (m *Model) initLists(width, height int) {
 
 	m.components = defaultList
 	m.components.Title = "ROSA AWS Component"
-	m.components.SetItems([]list.Item{
-		mirrosa.Vpc{},
-		mirrosa.DhcpOptions{},
-		mirrosa.SecurityGroup{},
-		mirrosa.VpcEndpointService{},
-		mirrosa.PublicHostedZone{},
-		mirrosa.PrivateHostedZone{},
-		mirrosa.Instances{},
-	})
+
+	var items []list.Item
+	for _, c := range mirrosa.RegisteredComponents() {
+		items = append(items, c)
+	}
+	m.components.SetItems(items)
 }
 
 func (m *Model) initViewport(width, height int) {
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/mjlshen/mirrosa/pkg/awsauth"
+	"github.com/mjlshen/mirrosa/pkg/cassette"
+	"github.com/mjlshen/mirrosa/pkg/mirrosa"
+	"github.com/mjlshen/mirrosa/pkg/ocm"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// clusterSource is where mirrosa gets the cluster it validates from: OCM by default, a local cluster file, or a
+// recorded cassette, and where it gets AWS credentials from
+type clusterSource struct {
+	clusterId   string
+	clusterFile string
+	replay      string
+
+	awsCredentials string
+	awsProfile     string
+	awsRoleArns    string
+	awsExternalId  string
+
+	awsEndpointUrl     string
+	ec2EndpointUrl     string
+	elbv2EndpointUrl   string
+	route53EndpointUrl string
+}
+
+// addSourceFlags registers the flags that select a clusterSource on f, verb describes what is done with the cluster
+func addSourceFlags(f *flag.FlagSet, verb string) *clusterSource {
+	s := &clusterSource{}
+	f.StringVar(&s.clusterId, "cluster-id", "", "OCM internal or external cluster id")
+	f.StringVar(&s.clusterFile, "cluster-file", "", verb+" the cluster defined in this OCM cluster JSON or YAML file without OCM, using the default AWS credentials")
+	f.StringVar(&s.replay, "replay", "", verb+" the cluster recorded in this cassette file offline, without OCM, backplane, or AWS")
+
+	sources := make([]string, len(awsauth.Sources))
+	for i, source := range awsauth.Sources {
+		sources[i] = string(source)
+	}
+	f.StringVar(&s.awsCredentials, "aws-credentials", "", "source of AWS credentials, one of: "+strings.Join(sources, ", ")+
+		", defaults to profile with -aws-profile, default with -cluster-file, and backplane otherwise")
+	f.StringVar(&s.awsProfile, "aws-profile", "", "name of the AWS profile to use for the profile AWS credential source")
+	f.StringVar(&s.awsRoleArns, "aws-role-arn", "", "comma-separated chain of IAM role ARNs to assume in order on top of the AWS credentials")
+	f.StringVar(&s.awsExternalId, "aws-external-id", "", "external id to pass when assuming the last role of -aws-role-arn")
+
+	f.StringVar(&s.awsEndpointUrl, "aws-endpoint-url", "", "endpoint URL of the EC2, ELBv2, and Route 53 APIs, e.g. http://localhost:4566 for LocalStack")
+	f.StringVar(&s.ec2EndpointUrl, "ec2-endpoint-url", "", "endpoint URL of the EC2 API, overrides -aws-endpoint-url")
+	f.StringVar(&s.elbv2EndpointUrl, "elbv2-endpoint-url", "", "endpoint URL of the ELBv2 API, overrides -aws-endpoint-url")
+	f.StringVar(&s.route53EndpointUrl, "route53-endpoint-url", "", "endpoint URL of the Route 53 API, overrides -aws-endpoint-url")
+
+	return s
+}
+
+// validate checks that exactly one clusterSource was selected
+func (s *clusterSource) validate() error {
+	var selected int
+	for _, v := range []string{s.clusterId, s.clusterFile, s.replay} {
+		if v != "" {
+			selected++
+		}
+	}
+
+	if selected == 0 {
+		return errors.New("cluster id must not be empty")
+	}
+
+	if selected > 1 {
+		return errors.New("only one of -cluster-id, -cluster-file, and -replay can be used")
+	}
+
+	endpoints := s.awsEndpoints()
+	if s.replay != "" {
+		if s.awsCredentials != "" || s.awsProfile != "" || s.awsRoleArns != "" || s.awsExternalId != "" {
+			return errors.New("AWS credentials can't be used with -replay, which never calls AWS")
+		}
+		if endpoints != (mirrosa.AwsEndpoints{}) {
+			return errors.New("AWS endpoint URLs can't be used with -replay, which never calls AWS")
+		}
+		return nil
+	}
+
+	for _, endpoint := range []string{endpoints.Ec2, endpoints.ElbV2, endpoints.Route53} {
+		if endpoint == "" {
+			continue
+		}
+
+		if u, err := url.Parse(endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid AWS endpoint URL %q, must be an absolute URL such as http://localhost:4566", endpoint)
+		}
+	}
+
+	opts := s.awsOptions()
+	if s.clusterFile != "" && opts.Source == awsauth.SourceBackplane {
+		return errors.New("backplane AWS credentials can't be used with -cluster-file, which doesn't use OCM")
+	}
+
+	return opts.Validate()
+}
+
+// awsEndpoints returns the selected AWS endpoint URLs, where service-specific URLs take precedence
+func (s *clusterSource) awsEndpoints() mirrosa.AwsEndpoints {
+	return mirrosa.AwsEndpoints{
+		Ec2:     cmp.Or(s.ec2EndpointUrl, s.awsEndpointUrl),
+		ElbV2:   cmp.Or(s.elbv2EndpointUrl, s.awsEndpointUrl),
+		Route53: cmp.Or(s.route53EndpointUrl, s.awsEndpointUrl),
+	}
+}
+
+// awsOptions returns the selected AWS credentials
+func (s *clusterSource) awsOptions() awsauth.Options {
+	opts := awsauth.Options{
+		Source:     awsauth.Source(s.awsCredentials),
+		Profile:    s.awsProfile,
+		RoleArns:   splitList(s.awsRoleArns),
+		ExternalId: s.awsExternalId,
+	}
+
+	if opts.Source == "" {
+		switch {
+		case s.awsProfile != "":
+			opts.Source = awsauth.SourceProfile
+		case s.clusterFile != "":
+			opts.Source = awsauth.SourceDefault
+		default:
+			opts.Source = awsauth.SourceBackplane
+		}
+	}
+
+	return opts
+}
+
+// newRosaClient returns a mirrosa client for the cluster from s
+func (s *clusterSource) newRosaClient(ctx context.Context, logger *slog.Logger, opts mirrosa.Options) (*mirrosa.Client, error) {
+	opts.AwsEndpoints = s.awsEndpoints()
+
+	switch {
+	case s.clusterFile != "":
+		cluster, err := ocm.ReadClusterFile(s.clusterFile)
+		if err != nil {
+			return nil, err
+		}
+
+		cfg, err := awsauth.LoadConfig(ctx, cluster.Region().ID(), s.awsOptions())
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Info("using cluster file", slog.String("path", s.clusterFile))
+		return mirrosa.NewRosaClientFromCluster(ctx, logger, cluster, cfg, opts)
+	case s.replay != "":
+		c, err := cassette.Load(s.replay)
+		if err != nil {
+			return nil, err
+		}
+
+		cluster, err := c.OcmCluster()
+		if err != nil {
+			return nil, err
+		}
+
+		cfg, err := c.AwsConfig(cluster.Region().ID())
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Info("replaying cassette", slog.String("path", s.replay), slog.Time("recorded", c.Recorded))
+		return mirrosa.NewRosaClientFromCluster(ctx, logger, cluster, cfg, opts)
+	default:
+		if awsOpts := s.awsOptions(); awsOpts.Source != awsauth.SourceBackplane {
+			opts.LoadAwsConfig = func(cluster *cmv1.Cluster) (aws.Config, error) {
+				logger.Info("using AWS credentials", slog.String("source", string(awsOpts.Source)))
+				return awsauth.LoadConfig(ctx, cluster.Region().ID(), awsOpts)
+			}
+		}
+
+		return mirrosa.NewRosaClient(ctx, logger, s.clusterId, opts)
+	}
+}